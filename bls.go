@@ -0,0 +1,339 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+	circlbls "github.com/cloudflare/circl/sign/bls"
+)
+
+// BLS signatures over BLS12-381 using the minimal-pubkey-size variant of the
+// proof of possession ciphersuite from the IETF BLS signature draft. Public
+// keys are compressed G1 points and signatures compressed G2 points, so many
+// signatures can be aggregated into one. The same ciphersuite is used by the
+// Ethereum beacon chain.
+//
+// Aggregating signatures over the same message is only safe when every public
+// key has proven possession of its private key, see PopProve and PopVerify.
+const (
+	// BLS12381PrivateKeyLength Private key length for BLS12-381, a big endian
+	// scalar
+	BLS12381PrivateKeyLength = 32
+	// BLS12381PublicKeyLength Public key length for BLS12-381, a compressed G1
+	// point
+	BLS12381PublicKeyLength = bls12381.G1SizeCompressed
+	// BLS12381SignatureLength Signature length for BLS12-381, a compressed G2
+	// point
+	BLS12381SignatureLength = bls12381.G2SizeCompressed
+	// BLS12381SeedLength Minimum length of the input keying material passed to
+	// BLS12381KeyPairFromSeed
+	BLS12381SeedLength = 32
+)
+
+// Domain separation tags for hashing messages and public keys to G2.
+const (
+	blsSignatureDST = "BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+	blsPopDST       = "BLS_POP_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_"
+)
+
+func init() {
+	mustRegisterSuite("bls12381_minpk_pop", NewBLS12381Signer, NewBLS12381Verifier)
+}
+
+// GenerateBLS12381KeyPair creates a BLS12-381 key pair, returning the 48 byte
+// public key and the 32 byte private key.
+func GenerateBLS12381KeyPair() (pub []byte, priv []byte, err error) {
+	seed := make([]byte, BLS12381SeedLength)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, nil, err
+	}
+	return BLS12381KeyPairFromSeed(seed)
+}
+
+// BLS12381KeyPairFromSeed derives a key pair from at least 32 bytes of input
+// keying material with the KeyGen procedure of the IETF BLS signature draft.
+func BLS12381KeyPairFromSeed(seed []byte) (pub []byte, priv []byte, err error) {
+	if len(seed) < BLS12381SeedLength {
+		return nil, nil, errors.New("seed should be at least 32 bytes got " + fmt.Sprint(len(seed)))
+	}
+	key, err := circlbls.KeyGen[circlbls.KeyG1SigG2](seed, []byte("BLS-SIG-KEYGEN-SALT-"), nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	priv, err = key.MarshalBinary()
+	if err != nil {
+		return nil, nil, err
+	}
+	pub, err = BLS12381PublicKeyFromPrivate(priv)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pub, priv, nil
+}
+
+// BLS12381PublicKeyFromPrivate return the 48 byte public key associated with
+// a BLS12-381 private key.
+func BLS12381PublicKeyFromPrivate(privKey []byte) ([]byte, error) {
+	key, err := parseBLSPrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	return blsPublicKey(key).BytesCompressed(), nil
+}
+
+func parseBLSPrivateKey(privKey []byte) (*bls12381.Scalar, error) {
+	if len(privKey) != BLS12381PrivateKeyLength {
+		return nil, errors.New("key should be 32 bytes got " + fmt.Sprint(len(privKey)))
+	}
+	key := new(bls12381.Scalar)
+	if err := key.UnmarshalBinary(privKey); err != nil || key.IsZero() == 1 {
+		return nil, errors.New("invalid BLS12-381 private key")
+	}
+	return key, nil
+}
+
+// Parses a public key, rejecting points outside of G1 and the identity as
+// KeyValidate does in the draft.
+func parseBLSPublicKey(pubKey []byte) (*bls12381.G1, error) {
+	if len(pubKey) != BLS12381PublicKeyLength {
+		return nil, fmt.Errorf("%w: key should be 48 bytes got %d", ErrInvalidPublicKey, len(pubKey))
+	}
+	key := new(bls12381.G1)
+	if err := key.SetBytes(pubKey); err != nil {
+		return nil, fmt.Errorf("%w: BLS12-381 %w", ErrInvalidPublicKey, err)
+	}
+	if key.IsIdentity() {
+		return nil, fmt.Errorf("%w: BLS12-381 identity", ErrInvalidPublicKey)
+	}
+	return key, nil
+}
+
+func parseBLSSignature(signature []byte) (*bls12381.G2, error) {
+	if len(signature) != BLS12381SignatureLength {
+		return nil, fmt.Errorf(
+			"%w: expected %d bytes, got %d bytes",
+			ErrSignatureLength, BLS12381SignatureLength, len(signature))
+	}
+	sig := new(bls12381.G2)
+	if err := sig.SetBytes(signature); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSignatureEncoding, err)
+	}
+	return sig, nil
+}
+
+func blsPublicKey(privKey *bls12381.Scalar) *bls12381.G1 {
+	pub := new(bls12381.G1)
+	pub.ScalarMult(privKey, bls12381.G1Generator())
+	return pub
+}
+
+// CoreSign from the draft, hashes message to G2 and multiplies it by the
+// private key.
+func blsCoreSign(privKey *bls12381.Scalar, message []byte, dst string) []byte {
+	var Q bls12381.G2
+	Q.Hash(message, []byte(dst))
+	Q.ScalarMult(privKey, &Q)
+	return Q.BytesCompressed()
+}
+
+// CoreAggregateVerify from the draft, checks that the product of
+// e(PK_i, H(message_i)) equals e(G1, signature). CoreVerify is the case of a
+// single key.
+func blsCoreAggregateVerify(
+	pubKeys []*bls12381.G1,
+	messages [][]byte,
+	signature []byte,
+	dst string,
+) error {
+	if len(pubKeys) == 0 || len(pubKeys) != len(messages) {
+		return errors.New("expected a message for each public key")
+	}
+	sig, err := parseBLSSignature(signature)
+	if err != nil {
+		return err
+	}
+	n := len(pubKeys)
+	listG1 := make([]*bls12381.G1, n+1)
+	listG2 := make([]*bls12381.G2, n+1)
+	signs := make([]int, n+1)
+	for i := range pubKeys {
+		Q := new(bls12381.G2)
+		Q.Hash(messages[i], []byte(dst))
+		listG1[i], listG2[i], signs[i] = pubKeys[i], Q, 1
+	}
+	listG1[n], listG2[n], signs[n] = bls12381.G1Generator(), sig, -1
+	if !bls12381.ProdPairFrac(listG1, listG2, signs).IsIdentity() {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+func parseBLSPublicKeys(pubKeys [][]byte) ([]*bls12381.G1, error) {
+	keys := make([]*bls12381.G1, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		key, err := parseBLSPublicKey(pubKey)
+		if err != nil {
+			return nil, err
+		}
+		keys[i] = key
+	}
+	return keys, nil
+}
+
+// AggregateSignatures combines BLS12-381 signatures into a single signature of
+// the same length. The result verifies with AggregateVerify, or with
+// FastAggregateVerify when all of the signatures are over the same message.
+func AggregateSignatures(signatures [][]byte) ([]byte, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("no signatures to aggregate")
+	}
+	var aggregate bls12381.G2
+	aggregate.SetIdentity()
+	for _, signature := range signatures {
+		sig, err := parseBLSSignature(signature)
+		if err != nil {
+			return nil, err
+		}
+		aggregate.Add(&aggregate, sig)
+	}
+	return aggregate.BytesCompressed(), nil
+}
+
+// AggregateVerify checks an aggregate BLS12-381 signature where pubKeys[i]
+// signed messages[i]. Returns false if any key is invalid or the lengths of
+// pubKeys and messages differ.
+func AggregateVerify(pubKeys [][]byte, messages [][]byte, signature []byte) bool {
+	keys, err := parseBLSPublicKeys(pubKeys)
+	if err != nil {
+		return false
+	}
+	return blsCoreAggregateVerify(keys, messages, signature, blsSignatureDST) == nil
+}
+
+// FastAggregateVerify checks an aggregate BLS12-381 signature where every key
+// in pubKeys signed the same message. Every key must have a verified proof of
+// possession, see PopVerify.
+func FastAggregateVerify(pubKeys [][]byte, message []byte, signature []byte) bool {
+	keys, err := parseBLSPublicKeys(pubKeys)
+	if err != nil || len(keys) == 0 {
+		return false
+	}
+	aggregate := new(bls12381.G1)
+	aggregate.SetIdentity()
+	for _, key := range keys {
+		aggregate.Add(aggregate, key)
+	}
+	return blsCoreAggregateVerify(
+		[]*bls12381.G1{aggregate}, [][]byte{message}, signature, blsSignatureDST) == nil
+}
+
+// PopProve creates a proof of possession for a BLS12-381 private key, a
+// signature over the public key using a separate domain.
+func PopProve(privKey []byte) ([]byte, error) {
+	key, err := parseBLSPrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	return blsCoreSign(key, blsPublicKey(key).BytesCompressed(), blsPopDST), nil
+}
+
+// PopVerify checks a proof of possession created with PopProve.
+func PopVerify(pubKey []byte, proof []byte) bool {
+	key, err := parseBLSPublicKey(pubKey)
+	if err != nil {
+		return false
+	}
+	return blsCoreAggregateVerify(
+		[]*bls12381.G1{key}, [][]byte{pubKey}, proof, blsPopDST) == nil
+}
+
+// NewBLS12381Verifier constructor for a BLS12-381 Verifier
+func NewBLS12381Verifier(pubKey []byte) (Verifier, error) {
+	key, err := parseBLSPublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return &blsVerifier{
+		publicKey: key,
+		suiteType: "bls12381_minpk_pop",
+	}, nil
+}
+
+// NewBLS12381Signer constructor for a BLS12-381 Signer
+func NewBLS12381Signer(privKey []byte) (Signer, error) {
+	key, err := parseBLSPrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	return &blsSigner{
+		privKey: key,
+		verifier: &blsVerifier{
+			publicKey: blsPublicKey(key),
+			suiteType: "bls12381_minpk_pop",
+		},
+	}, nil
+}
+
+// blsVerifier Verifies a BLS12-381 signature from a single key
+type blsVerifier struct {
+	publicKey *bls12381.G1
+	suiteType string
+}
+
+func (s *blsVerifier) SuiteType() string {
+	return s.suiteType
+}
+
+// PublicKey returns the public key, the 48 byte compressed G1 point, see PublicKeyHolder.
+func (s *blsVerifier) PublicKey() []byte {
+	return s.publicKey.BytesCompressed()
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *blsVerifier) KeyID() string {
+	return KeyIDFromPublicKey(s.PublicKey())
+}
+
+func (s *blsVerifier) Verify(toVerify []byte, signature []byte) bool {
+	return s.VerifyErr(toVerify, signature) == nil
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *blsVerifier) VerifyErr(toVerify []byte, signature []byte) error {
+	return blsCoreAggregateVerify(
+		[]*bls12381.G1{s.publicKey}, [][]byte{toVerify}, signature, blsSignatureDST)
+}
+
+type blsSigner struct {
+	privKey  *bls12381.Scalar
+	verifier *blsVerifier
+}
+
+func (s *blsSigner) Sign(toSign []byte) ([]byte, error) {
+	return blsCoreSign(s.privKey, toSign, blsSignatureDST), nil
+}
+
+func (s *blsSigner) Verify(toVerify []byte, signature []byte) bool {
+	return s.verifier.Verify(toVerify, signature)
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *blsSigner) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifier.VerifyErr(toVerify, signature)
+}
+
+func (s *blsSigner) SuiteType() string {
+	return s.verifier.SuiteType()
+}
+
+// PublicKey returns the public key, see PublicKeyHolder.
+func (s *blsSigner) PublicKey() []byte {
+	return s.verifier.PublicKey()
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *blsSigner) KeyID() string {
+	return s.verifier.KeyID()
+}
@@ -0,0 +1,286 @@
+package crypto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/cloudflare/circl/ecc/bls12381"
+)
+
+// Keys and signatures from the Ethereum consensus spec BLS sign test vectors,
+// which use the same ciphersuite.
+var blsTestCases = []struct {
+	name      string
+	privKey   string
+	pubKey    string
+	message   string
+	signature string
+}{
+	{
+		"key_1_zero_message",
+		"263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3",
+		"a491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"b6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55",
+	},
+	{
+		"key_2_zero_message",
+		"47b8192d77bf871b62e87859d653922725724a5c031afeabc60bcef5ff665138",
+		"b301803f8b5ac4a1133581fc676dfedc60d891dd5fa99028805e5ea5b08d3491af75d0707adab3b70c6a6a580217bf81",
+		"0000000000000000000000000000000000000000000000000000000000000000",
+		"b23c46be3a001c63ca711f87a005c200cc550b9429d5f4eb38d74322144f1b63926da3388979e5321012fb1a0526bcd100b5ef5fe72628ce4cd5e904aeaa3279527843fae5ca9ca675f4f51ed8f83bbf7155da9ecc9663100a885d5dc6df96d9",
+	},
+}
+
+func TestBLS12381SuiteType(t *testing.T) {
+	signer := blsSigner{
+		verifier: &blsVerifier{suiteType: "Test"},
+	}
+	expected := "Test"
+	result := signer.SuiteType()
+	if expected != result {
+		t.Errorf("Got %s, expected %s", result, expected)
+	}
+}
+
+func TestNewBLS12381Signer(t *testing.T) {
+	for _, tt := range blsTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			privKey, _ := FromHex(tt.privKey)
+			message, _ := FromHex(tt.message)
+			signer, err := NewBLS12381Signer(privKey)
+			if err != nil {
+				t.Fatalf("Got a constructor error %s", err)
+			}
+			if signer.SuiteType() != "bls12381_minpk_pop" {
+				t.Errorf("Expected suite type bls12381_minpk_pop got %s", signer.SuiteType())
+			}
+			signature, err := signer.Sign(message)
+			if err != nil {
+				t.Fatalf("Got a sign error %s", err)
+			}
+			if ToHex(signature) != tt.signature {
+				t.Errorf("Expected %s, signature was %x.", tt.signature, signature)
+			}
+			if !signer.Verify(message, signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+		})
+	}
+}
+
+func TestNewBLS12381Verifier(t *testing.T) {
+	for _, tt := range blsTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			pubKey, _ := FromHex(tt.pubKey)
+			message, _ := FromHex(tt.message)
+			signature, _ := FromHex(tt.signature)
+			verifier, err := NewBLS12381Verifier(pubKey)
+			if err != nil {
+				t.Fatalf("Got a constructor error %s", err)
+			}
+			if !verifier.Verify(message, signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+			if verifier.Verify(append(message, 1), signature) {
+				t.Errorf("Expected signature over another message to not verify.")
+			}
+			if verifier.Verify(message, signature[1:]) {
+				t.Errorf("Expected short signature to not verify.")
+			}
+		})
+	}
+}
+
+func TestBLS12381PublicFromPrivate(t *testing.T) {
+	for _, tt := range blsTestCases {
+		privKey, _ := FromHex(tt.privKey)
+		pubKey, _ := BLS12381PublicKeyFromPrivate(privKey)
+		if ToHex(pubKey) != tt.pubKey {
+			t.Errorf("Expected %s, pubkey was %x.", tt.pubKey, pubKey)
+		}
+	}
+}
+
+func TestBLS12381KeyErrors(t *testing.T) {
+	key := []byte{1, 2, 3}
+	if _, err := NewBLS12381Signer(key); err == nil {
+		t.Errorf("Did not get signature error.")
+	}
+	if _, err := NewBLS12381Verifier(key); err == nil {
+		t.Errorf("Did not get verifier error.")
+	}
+	if _, err := NewBLS12381Signer(make([]byte, BLS12381PrivateKeyLength)); err == nil {
+		t.Errorf("Expected error for zero private key.")
+	}
+	if _, err := NewBLS12381Signer(bls12381.Order()); err == nil {
+		t.Errorf("Expected error for private key equal to the group order.")
+	}
+	identity := make([]byte, BLS12381PublicKeyLength)
+	identity[0] = 0xc0
+	if _, err := NewBLS12381Verifier(identity); err == nil {
+		t.Errorf("Expected error for the identity public key.")
+	}
+}
+
+func TestBLS12381KeyPairFromSeed(t *testing.T) {
+	seed := make([]byte, BLS12381SeedLength)
+	pub1, priv1, err := BLS12381KeyPairFromSeed(seed)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub2, priv2, _ := BLS12381KeyPairFromSeed(seed)
+	if !reflect.DeepEqual(pub1, pub2) || !reflect.DeepEqual(priv1, priv2) {
+		t.Errorf("Expected the same seed to derive the same keys.")
+	}
+	if _, _, err := BLS12381KeyPairFromSeed(seed[1:]); err == nil {
+		t.Errorf("Did not get seed length error.")
+	}
+}
+
+func generateBLSKeys(t *testing.T, n int) (pubKeys [][]byte, signers []Signer) {
+	for i := 0; i < n; i++ {
+		pub, priv, err := GenerateBLS12381KeyPair()
+		if err != nil {
+			t.Fatalf("Got a key generation error %s", err)
+		}
+		signer, _ := NewBLS12381Signer(priv)
+		pubKeys = append(pubKeys, pub)
+		signers = append(signers, signer)
+	}
+	return pubKeys, signers
+}
+
+func TestBLS12381AggregateVerify(t *testing.T) {
+	pubKeys, signers := generateBLSKeys(t, 3)
+	messages := [][]byte{{1}, {2}, {3}}
+	var signatures [][]byte
+	for i, signer := range signers {
+		signature, _ := signer.Sign(messages[i])
+		signatures = append(signatures, signature)
+	}
+
+	aggregate, err := AggregateSignatures(signatures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aggregate) != BLS12381SignatureLength {
+		t.Errorf("Expected %d byte signature, got %d", BLS12381SignatureLength, len(aggregate))
+	}
+	if !AggregateVerify(pubKeys, messages, aggregate) {
+		t.Errorf("Expected aggregate signature to verify.")
+	}
+	if AggregateVerify(pubKeys, [][]byte{{1}, {3}, {2}}, aggregate) {
+		t.Errorf("Expected aggregate signature over swapped messages to not verify.")
+	}
+	if AggregateVerify(pubKeys[:2], messages[:2], aggregate) {
+		t.Errorf("Expected aggregate signature with a missing signer to not verify.")
+	}
+	if AggregateVerify(pubKeys, messages[:2], aggregate) {
+		t.Errorf("Expected mismatched keys and messages to not verify.")
+	}
+	if AggregateVerify(nil, nil, aggregate) {
+		t.Errorf("Expected no keys to not verify.")
+	}
+}
+
+func TestBLS12381FastAggregateVerify(t *testing.T) {
+	pubKeys, signers := generateBLSKeys(t, 3)
+	message := []byte("block 1")
+	var signatures [][]byte
+	for _, signer := range signers {
+		signature, _ := signer.Sign(message)
+		signatures = append(signatures, signature)
+	}
+
+	aggregate, _ := AggregateSignatures(signatures)
+	if !FastAggregateVerify(pubKeys, message, aggregate) {
+		t.Errorf("Expected aggregate signature to verify.")
+	}
+	if !AggregateVerify(pubKeys, [][]byte{message, message, message}, aggregate) {
+		t.Errorf("Expected aggregate signature to verify with AggregateVerify.")
+	}
+	if FastAggregateVerify(pubKeys, []byte("block 2"), aggregate) {
+		t.Errorf("Expected aggregate signature over another message to not verify.")
+	}
+	if FastAggregateVerify(pubKeys[1:], message, aggregate) {
+		t.Errorf("Expected aggregate signature with a missing signer to not verify.")
+	}
+	if FastAggregateVerify(nil, message, aggregate) {
+		t.Errorf("Expected no keys to not verify.")
+	}
+}
+
+func TestBLS12381AggregateSignaturesErrors(t *testing.T) {
+	if _, err := AggregateSignatures(nil); err == nil {
+		t.Errorf("Expected error aggregating no signatures.")
+	}
+	signature, _ := FromHex(blsTestCases[0].signature)
+	_, err := AggregateSignatures([][]byte{signature, signature[1:]})
+	if !errors.Is(err, ErrSignatureLength) {
+		t.Errorf("Expected ErrSignatureLength, got %v", err)
+	}
+	bad := append([]byte{}, signature...)
+	bad[0] ^= 0x40
+	_, err = AggregateSignatures([][]byte{signature, bad})
+	if !errors.Is(err, ErrSignatureEncoding) {
+		t.Errorf("Expected ErrSignatureEncoding, got %v", err)
+	}
+}
+
+func TestBLS12381Pop(t *testing.T) {
+	pubKeys, _ := generateBLSKeys(t, 1)
+	_, priv, _ := GenerateBLS12381KeyPair()
+	privKey, _ := FromHex(blsTestCases[0].privKey)
+	pubKey, _ := FromHex(blsTestCases[0].pubKey)
+
+	proof, err := PopProve(privKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !PopVerify(pubKey, proof) {
+		t.Errorf("Expected proof of possession to verify.")
+	}
+	if PopVerify(pubKeys[0], proof) {
+		t.Errorf("Expected proof for another key to not verify.")
+	}
+	otherProof, _ := PopProve(priv)
+	if PopVerify(pubKey, otherProof) {
+		t.Errorf("Expected proof from another key to not verify.")
+	}
+
+	// A proof is not a signature over the public key, the domains differ.
+	signer, _ := NewBLS12381Signer(privKey)
+	signature, _ := signer.Sign(pubKey)
+	if PopVerify(pubKey, signature) {
+		t.Errorf("Expected signature over the public key to not verify as a proof.")
+	}
+}
+
+// A rogue key is chosen as x*G - victim, the attacker alone can then create an
+// aggregate signature that verifies for both keys. Without the private key
+// for the rogue key the attacker can not prove possession of it.
+func TestBLS12381PopBlocksRogueKey(t *testing.T) {
+	victim, _ := FromHex(blsTestCases[0].pubKey)
+	victimKey, _ := parseBLSPublicKey(victim)
+	_, attackerPriv, _ := GenerateBLS12381KeyPair()
+	attacker, _ := parseBLSPrivateKey(attackerPriv)
+
+	negVictim := *victimKey
+	negVictim.Neg()
+	rogueKey := blsPublicKey(attacker)
+	rogueKey.Add(rogueKey, &negVictim)
+	rogue := rogueKey.BytesCompressed()
+
+	message := []byte("transfer everything")
+	forged := blsCoreSign(attacker, message, blsSignatureDST)
+	if !FastAggregateVerify([][]byte{victim, rogue}, message, forged) {
+		t.Fatalf("Expected rogue key attack to succeed without proofs of possession.")
+	}
+
+	proof, _ := PopProve(attackerPriv)
+	if PopVerify(rogue, proof) {
+		t.Errorf("Expected rogue key to have no valid proof of possession.")
+	}
+}
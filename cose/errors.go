@@ -0,0 +1,23 @@
+package cose
+
+import "errors"
+
+var (
+	// ErrMalformed occurs when a COSE message or key can not be decoded
+	ErrMalformed = errors.New("malformed COSE structure")
+
+	// ErrAlgorithm occurs when a suite has no COSE algorithm, or when a message
+	// names a different algorithm than the one the verifier implements
+	ErrAlgorithm = errors.New("unexpected COSE algorithm")
+
+	// ErrCritical occurs when a message lists critical header parameters, none
+	// are supported
+	ErrCritical = errors.New("unsupported critical header parameter")
+
+	// ErrSignature occurs when a COSE signature does not verify
+	ErrSignature = errors.New("COSE signature verification failed")
+
+	// ErrUnsupportedKey occurs when a COSE_Key has a key type or curve that has
+	// no suite in this package
+	ErrUnsupportedKey = errors.New("unsupported COSE key")
+)
@@ -0,0 +1,161 @@
+package cose
+
+import (
+	"crypto/elliptic"
+	"fmt"
+
+	"github.com/kochavalabs/crypto"
+)
+
+// COSE_Key labels and values from RFC 9052 and RFC 9053.
+const (
+	keyLabelType  int64 = 1
+	keyLabelID    int64 = 2
+	keyLabelAlg   int64 = 3
+	keyLabelCurve int64 = -1
+	keyLabelX     int64 = -2
+	keyLabelY     int64 = -3
+	keyLabelD     int64 = -4
+
+	keyTypeOKP   int64 = 1
+	keyTypeEC2   int64 = 2
+	curveP256    int64 = 1
+	curveEd25519 int64 = 6
+)
+
+const (
+	ed25519PublicKeyLength = 32
+	p256CoordinateLength   = 32
+)
+
+// Key is a COSE_Key holding an ed25519 or P256 public key. PublicKey is in
+// the format taken by the suite's verifier constructor: the 32 byte ed25519
+// key, or X || Y for P256.
+type Key struct {
+	// SuiteType is the verifier suite of the key, ed25519 or
+	// ecdsa_P256_sha-256. Signer suite types of the same algorithm, e.g.
+	// ecdsa_P256_sha-256_rfc6979, are accepted when encoding.
+	SuiteType string
+	KeyID     []byte
+	PublicKey []byte
+}
+
+// Verifier constructs a Verifier for the key.
+func (k *Key) Verifier() (crypto.Verifier, error) {
+	return crypto.NewVerifierForSuite(k.SuiteType, k.PublicKey)
+}
+
+// MarshalCBOR encodes the key as a COSE_Key map, including its algorithm.
+func (k *Key) MarshalCBOR() ([]byte, error) {
+	alg, err := AlgorithmForSuite(k.SuiteType)
+	if err != nil {
+		return nil, err
+	}
+	encoded := map[int64]interface{}{keyLabelAlg: alg}
+	switch alg {
+	case AlgorithmEdDSA:
+		if len(k.PublicKey) != ed25519PublicKeyLength {
+			return nil, fmt.Errorf("%w: ed25519 key should be 32 bytes got %d", ErrUnsupportedKey, len(k.PublicKey))
+		}
+		encoded[keyLabelType] = keyTypeOKP
+		encoded[keyLabelCurve] = curveEd25519
+		encoded[keyLabelX] = k.PublicKey
+	case AlgorithmES256:
+		if len(k.PublicKey) != 2*p256CoordinateLength {
+			return nil, fmt.Errorf("%w: P256 key should be 64 bytes got %d", ErrUnsupportedKey, len(k.PublicKey))
+		}
+		encoded[keyLabelType] = keyTypeEC2
+		encoded[keyLabelCurve] = curveP256
+		encoded[keyLabelX] = k.PublicKey[:p256CoordinateLength]
+		encoded[keyLabelY] = k.PublicKey[p256CoordinateLength:]
+	}
+	if len(k.KeyID) > 0 {
+		encoded[keyLabelID] = k.KeyID
+	}
+	return encMode.Marshal(encoded)
+}
+
+// UnmarshalCBOR decodes a COSE_Key holding an ed25519 or P256 public key.
+// Compressed P256 points are accepted. Keys with another type or curve, an
+// algorithm that does not match the curve, or private key material are
+// rejected.
+func (k *Key) UnmarshalCBOR(data []byte) error {
+	var decoded map[int64]interface{}
+	if err := decMode.Unmarshal(data, &decoded); err != nil {
+		return fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	if _, ok := decoded[keyLabelD]; ok {
+		return fmt.Errorf("%w: private keys are not supported", ErrUnsupportedKey)
+	}
+	keyType, _ := toInt64(decoded[keyLabelType])
+	curve, _ := toInt64(decoded[keyLabelCurve])
+	x, _ := decoded[keyLabelX].([]byte)
+
+	var key Key
+	var alg int64
+	switch {
+	case keyType == keyTypeOKP && curve == curveEd25519:
+		if len(x) != ed25519PublicKeyLength {
+			return fmt.Errorf("%w: ed25519 key should be 32 bytes got %d", ErrMalformed, len(x))
+		}
+		key.SuiteType = "ed25519"
+		key.PublicKey = x
+		alg = AlgorithmEdDSA
+	case keyType == keyTypeEC2 && curve == curveP256:
+		publicKey, err := decodeP256Point(x, decoded[keyLabelY])
+		if err != nil {
+			return err
+		}
+		key.SuiteType = "ecdsa_P256_sha-256"
+		key.PublicKey = publicKey
+		alg = AlgorithmES256
+	default:
+		return fmt.Errorf("%w: key type %v curve %v", ErrUnsupportedKey, decoded[keyLabelType], decoded[keyLabelCurve])
+	}
+
+	if value, ok := decoded[keyLabelAlg]; ok {
+		if keyAlg, isInt := toInt64(value); !isInt || keyAlg != alg {
+			return fmt.Errorf("%w: key algorithm %v does not match the curve", ErrAlgorithm, value)
+		}
+	}
+	if value, ok := decoded[keyLabelID]; ok {
+		keyID, isBytes := value.([]byte)
+		if !isBytes {
+			return fmt.Errorf("%w: kid must be a byte string", ErrMalformed)
+		}
+		key.KeyID = keyID
+	}
+	if _, err := key.Verifier(); err != nil {
+		return fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	*k = key
+	return nil
+}
+
+// Returns X || Y, y is either the Y coordinate or the sign bit of a
+// compressed point.
+func decodeP256Point(x []byte, y interface{}) ([]byte, error) {
+	if len(x) != p256CoordinateLength {
+		return nil, fmt.Errorf("%w: P256 x should be 32 bytes got %d", ErrMalformed, len(x))
+	}
+	switch y := y.(type) {
+	case []byte:
+		if len(y) != p256CoordinateLength {
+			return nil, fmt.Errorf("%w: P256 y should be 32 bytes got %d", ErrMalformed, len(y))
+		}
+		return append(append([]byte{}, x...), y...), nil
+	case bool:
+		compressed := append([]byte{0x02}, x...)
+		if y {
+			compressed[0] = 0x03
+		}
+		pointX, pointY := elliptic.UnmarshalCompressed(elliptic.P256(), compressed)
+		if pointX == nil {
+			return nil, fmt.Errorf("%w: invalid compressed P256 point", ErrMalformed)
+		}
+		return append(
+			pointX.FillBytes(make([]byte, p256CoordinateLength)),
+			pointY.FillBytes(make([]byte, p256CoordinateLength))...), nil
+	}
+	return nil, fmt.Errorf("%w: missing P256 y", ErrMalformed)
+}
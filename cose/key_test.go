@@ -0,0 +1,145 @@
+package cose
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kochavalabs/crypto"
+)
+
+// COSE_Keys encoded by github.com/veraison/go-cose.
+const ed25519KeyHex = "a4010103272006215820d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a"
+const p256KeyHex = "a60102024470323536032620012158200e609d4eea6ecac33fd083bf108e90db5a31fbf9239bc5cc19a8a6dd10b61050225820c746f61b03ab399bcc5d18bd33953b4e73a4fdf7529f58747304a32c4814d24e"
+
+func TestKeyMarshalCBOR(t *testing.T) {
+	edPub, _, _ := crypto.Ed25519KeyPairFromSeed(mustHex(ed25519Seed))
+	testCases := []struct {
+		key      Key
+		expected string
+	}{
+		{Key{SuiteType: "ed25519", PublicKey: edPub}, ed25519KeyHex},
+		{Key{SuiteType: "ecdsa_P256_sha-256", KeyID: []byte("p256"), PublicKey: mustHex(p256PubHex)}, p256KeyHex},
+		{Key{SuiteType: "ecdsa_P256_sha-256_rfc6979", KeyID: []byte("p256"), PublicKey: mustHex(p256PubHex)}, p256KeyHex},
+	}
+	for _, tc := range testCases {
+		encoded, err := tc.key.MarshalCBOR()
+		if err != nil {
+			t.Fatalf("Error encoding: %s", err)
+		}
+		if crypto.ToHex(encoded) != tc.expected {
+			t.Errorf("Got %x, expected %s", encoded, tc.expected)
+		}
+	}
+
+	invalid := []Key{
+		{SuiteType: "ed25519", PublicKey: edPub[:31]},
+		{SuiteType: "ecdsa_P256_sha-256", PublicKey: mustHex(p256PubHex)[:32]},
+	}
+	for _, key := range invalid {
+		if _, err := key.MarshalCBOR(); !errors.Is(err, ErrUnsupportedKey) {
+			t.Errorf("Got %v, expected %v", err, ErrUnsupportedKey)
+		}
+	}
+	unknown := Key{SuiteType: "ecdsa_secp256k1_sha-256", PublicKey: mustHex(p256PubHex)}
+	if _, err := unknown.MarshalCBOR(); !errors.Is(err, ErrAlgorithm) {
+		t.Errorf("Got %v, expected %v", err, ErrAlgorithm)
+	}
+}
+
+func TestKeyUnmarshalCBOR(t *testing.T) {
+	var key Key
+	if err := key.UnmarshalCBOR(mustHex(p256KeyHex)); err != nil {
+		t.Fatalf("Error decoding: %s", err)
+	}
+	if key.SuiteType != "ecdsa_P256_sha-256" || string(key.KeyID) != "p256" ||
+		!bytes.Equal(key.PublicKey, mustHex(p256PubHex)) {
+		t.Errorf("Got %+v, expected the P256 test key", key)
+	}
+
+	// The key's verifier verifies the go-cose ES256 message.
+	verifier, err := key.Verifier()
+	if err != nil {
+		t.Fatalf("Error creating the verifier: %s", err)
+	}
+	if _, err := VerifySign1(verifier, mustHex(es256Sign1Hex), nil); err != nil {
+		t.Errorf("Error verifying: %s", err)
+	}
+
+	if err := key.UnmarshalCBOR(mustHex(ed25519KeyHex)); err != nil {
+		t.Fatalf("Error decoding: %s", err)
+	}
+	verifier, _ = key.Verifier()
+	if _, err := VerifySign1(verifier, mustHex(eddsaSign1Hex), []byte("aad")); err != nil {
+		t.Errorf("Error verifying: %s", err)
+	}
+}
+
+func TestKeyUnmarshalCBORCompressed(t *testing.T) {
+	pub := mustHex(p256PubHex)
+	// The Y coordinate of the test key is even.
+	encoded, _ := encMode.Marshal(map[int64]interface{}{
+		keyLabelType: keyTypeEC2, keyLabelCurve: curveP256, keyLabelX: pub[:32], keyLabelY: false,
+	})
+	var key Key
+	if err := key.UnmarshalCBOR(encoded); err != nil {
+		t.Fatalf("Error decoding: %s", err)
+	}
+	if !bytes.Equal(key.PublicKey, pub) {
+		t.Errorf("Got %x, expected %x", key.PublicKey, pub)
+	}
+}
+
+func TestKeyUnmarshalCBORErrors(t *testing.T) {
+	edPub, edPriv, _ := crypto.Ed25519KeyPairFromSeed(mustHex(ed25519Seed))
+	pub := mustHex(p256PubHex)
+	offCurve := append(append([]byte{}, pub[:32]...), pub[:32]...)
+	testCases := []struct {
+		name     string
+		key      map[int64]interface{}
+		expected error
+	}{
+		{"no key type", map[int64]interface{}{keyLabelCurve: curveEd25519, keyLabelX: edPub}, ErrUnsupportedKey},
+		{"X25519", map[int64]interface{}{keyLabelType: keyTypeOKP, keyLabelCurve: 4, keyLabelX: edPub}, ErrUnsupportedKey},
+		{"P384", map[int64]interface{}{keyLabelType: keyTypeEC2, keyLabelCurve: 2, keyLabelX: pub[:32], keyLabelY: pub[32:]}, ErrUnsupportedKey},
+		{"curve mismatch", map[int64]interface{}{keyLabelType: keyTypeEC2, keyLabelCurve: curveEd25519, keyLabelX: edPub}, ErrUnsupportedKey},
+		{
+			"private key",
+			map[int64]interface{}{keyLabelType: keyTypeOKP, keyLabelCurve: curveEd25519, keyLabelX: edPub, keyLabelD: edPriv[:32]},
+			ErrUnsupportedKey,
+		},
+		{
+			"alg mismatch",
+			map[int64]interface{}{keyLabelType: keyTypeOKP, keyLabelCurve: curveEd25519, keyLabelX: edPub, keyLabelAlg: AlgorithmES256},
+			ErrAlgorithm,
+		},
+		{"short x", map[int64]interface{}{keyLabelType: keyTypeOKP, keyLabelCurve: curveEd25519, keyLabelX: edPub[:31]}, ErrMalformed},
+		{"missing y", map[int64]interface{}{keyLabelType: keyTypeEC2, keyLabelCurve: curveP256, keyLabelX: pub[:32]}, ErrMalformed},
+		{
+			"off curve",
+			map[int64]interface{}{keyLabelType: keyTypeEC2, keyLabelCurve: curveP256, keyLabelX: offCurve[:32], keyLabelY: offCurve[32:]},
+			ErrMalformed,
+		},
+		{
+			"text kid",
+			map[int64]interface{}{keyLabelType: keyTypeOKP, keyLabelCurve: curveEd25519, keyLabelX: edPub, keyLabelID: "kid"},
+			ErrMalformed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			encoded, err := encMode.Marshal(tc.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var key Key
+			if err := key.UnmarshalCBOR(encoded); !errors.Is(err, tc.expected) {
+				t.Errorf("Got %v, expected %v", err, tc.expected)
+			}
+		})
+	}
+	var key Key
+	if err := key.UnmarshalCBOR([]byte{0xa1}); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Got %v, expected %v", err, ErrMalformed)
+	}
+}
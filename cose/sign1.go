@@ -0,0 +1,247 @@
+// Package cose creates and verifies COSE_Sign1 messages and encodes COSE_Key
+// public keys (RFC 9052, RFC 9053) with the Signers and Verifiers of the
+// crypto package.
+//
+// As in the jose package the algorithm is taken from the suite type of the
+// signer or verifier. A verifier only accepts messages whose protected alg
+// header names its own algorithm.
+package cose
+
+import (
+	"fmt"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/kochavalabs/crypto"
+)
+
+// COSE algorithm identifiers from RFC 9053.
+const (
+	AlgorithmEdDSA int64 = -8
+	AlgorithmES256 int64 = -7
+)
+
+// Header parameter labels from RFC 9052.
+const (
+	HeaderAlgorithm   int64 = 1
+	HeaderCritical    int64 = 2
+	HeaderContentType int64 = 3
+	HeaderKeyID       int64 = 4
+)
+
+// Tag number of a COSE_Sign1 message, encoded as the single byte 0xd2.
+const sign1Tag = 18
+
+// Suites whose signatures are valid COSE signatures of an algorithm.
+var suiteAlgorithms = map[string]int64{
+	"ed25519":                    AlgorithmEdDSA,
+	"ecdsa_P256_sha-256":         AlgorithmES256,
+	"ecdsa_P256_sha-256_rfc6979": AlgorithmES256,
+	"ecdsa_P256_sha-256_indet":   AlgorithmES256,
+}
+
+// AlgorithmForSuite returns the COSE algorithm of a suite type, e.g.
+// AlgorithmES256 for ecdsa_P256_sha-256_rfc6979.
+func AlgorithmForSuite(suiteType string) (int64, error) {
+	alg, ok := suiteAlgorithms[suiteType]
+	if !ok {
+		return 0, fmt.Errorf("%w: suite %s has no COSE algorithm", ErrAlgorithm, suiteType)
+	}
+	return alg, nil
+}
+
+// Header maps header parameter labels to values. Only integer labels are
+// supported, messages with text labels are rejected.
+type Header map[int64]interface{}
+
+// Sign1Message is a decoded COSE_Sign1 message.
+type Sign1Message struct {
+	Protected   Header
+	Unprotected Header
+	Payload     []byte
+	Signature   []byte
+}
+
+type sign1Message struct {
+	_           struct{} `cbor:",toarray"`
+	Protected   []byte
+	Unprotected Header
+	Payload     []byte
+	Signature   []byte
+}
+
+var (
+	encMode cbor.EncMode
+	decMode cbor.DecMode
+)
+
+func init() {
+	var err error
+	// Protected headers and Sig_structures are encoded deterministically.
+	encMode, err = cbor.CoreDetEncOptions().EncMode()
+	if err != nil {
+		panic(err)
+	}
+	decMode, err = cbor.DecOptions{
+		DupMapKey:   cbor.DupMapKeyEnforcedAPF,
+		IndefLength: cbor.IndefLengthForbidden,
+		TagsMd:      cbor.TagsForbidden,
+		IntDec:      cbor.IntDecConvertSignedOrFail,
+	}.DecMode()
+	if err != nil {
+		panic(err)
+	}
+}
+
+// Sign1 signs payload and returns a tagged COSE_Sign1 message. The algorithm
+// is added to the protected header from the signer's suite, externalAAD is
+// signed but not included in the message and may be nil.
+func Sign1(
+	signer crypto.Signer,
+	protected Header,
+	unprotected Header,
+	payload []byte,
+	externalAAD []byte,
+) ([]byte, error) {
+	alg, err := AlgorithmForSuite(signer.SuiteType())
+	if err != nil {
+		return nil, err
+	}
+	header := Header{}
+	for label, value := range protected {
+		header[label] = value
+	}
+	if value, ok := header[HeaderAlgorithm]; ok {
+		if headerAlg, isInt := toInt64(value); !isInt || headerAlg != alg {
+			return nil, fmt.Errorf("%w: header has %v, signer is %d", ErrAlgorithm, value, alg)
+		}
+	}
+	header[HeaderAlgorithm] = alg
+	if unprotected == nil {
+		unprotected = Header{}
+	}
+	if err := checkHeaders(header, unprotected); err != nil {
+		return nil, err
+	}
+
+	rawProtected, err := encMode.Marshal(header)
+	if err != nil {
+		return nil, err
+	}
+	if payload == nil {
+		payload = []byte{}
+	}
+	toSign, err := sigStructure(rawProtected, externalAAD, payload)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := signer.Sign(toSign)
+	if err != nil {
+		return nil, err
+	}
+	return encMode.Marshal(cbor.Tag{
+		Number: sign1Tag,
+		Content: sign1Message{
+			Protected:   rawProtected,
+			Unprotected: unprotected,
+			Payload:     payload,
+			Signature:   signature,
+		},
+	})
+}
+
+// VerifySign1 verifies a tagged or untagged COSE_Sign1 message, returning it
+// decoded. externalAAD must be the one given to Sign1. Messages with a
+// detached payload are not supported.
+func VerifySign1(verifier crypto.Verifier, data []byte, externalAAD []byte) (*Sign1Message, error) {
+	content := data
+	if len(data) > 0 && data[0] == 0xc0|sign1Tag {
+		content = data[1:]
+	}
+	var decoded sign1Message
+	if err := decMode.Unmarshal(content, &decoded); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	if decoded.Payload == nil {
+		return nil, fmt.Errorf("%w: detached payloads are not supported", ErrMalformed)
+	}
+	if decoded.Signature == nil {
+		return nil, fmt.Errorf("%w: missing signature", ErrMalformed)
+	}
+	protected := Header{}
+	if len(decoded.Protected) > 0 {
+		if err := decMode.Unmarshal(decoded.Protected, &protected); err != nil {
+			return nil, fmt.Errorf("%w: protected header: %s", ErrMalformed, err)
+		}
+	}
+	if decoded.Unprotected == nil {
+		decoded.Unprotected = Header{}
+	}
+	if err := checkHeaders(protected, decoded.Unprotected); err != nil {
+		return nil, err
+	}
+
+	expected, err := AlgorithmForSuite(verifier.SuiteType())
+	if err != nil {
+		return nil, err
+	}
+	value, ok := protected[HeaderAlgorithm]
+	if !ok {
+		return nil, fmt.Errorf("%w: missing alg header", ErrMalformed)
+	}
+	if alg, isInt := toInt64(value); !isInt || alg != expected {
+		return nil, fmt.Errorf("%w: expected %d, got %v", ErrAlgorithm, expected, value)
+	}
+
+	toVerify, err := sigStructure(decoded.Protected, externalAAD, decoded.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if !verifier.Verify(toVerify, decoded.Signature) {
+		return nil, ErrSignature
+	}
+	return &Sign1Message{
+		Protected:   protected,
+		Unprotected: decoded.Unprotected,
+		Payload:     decoded.Payload,
+		Signature:   decoded.Signature,
+	}, nil
+}
+
+// Checks that the headers are disjoint, that alg and crit are protected and
+// that there are no critical parameters, as none are supported.
+func checkHeaders(protected Header, unprotected Header) error {
+	for label := range unprotected {
+		if _, ok := protected[label]; ok {
+			return fmt.Errorf("%w: header label %d is both protected and unprotected", ErrMalformed, label)
+		}
+		if label == HeaderAlgorithm || label == HeaderCritical {
+			return fmt.Errorf("%w: header label %d must be protected", ErrMalformed, label)
+		}
+	}
+	if _, ok := protected[HeaderCritical]; ok {
+		return ErrCritical
+	}
+	return nil
+}
+
+// The Sig_structure of RFC 9052 section 4.4 for a COSE_Sign1.
+func sigStructure(rawProtected []byte, externalAAD []byte, payload []byte) ([]byte, error) {
+	if rawProtected == nil {
+		rawProtected = []byte{}
+	}
+	if externalAAD == nil {
+		externalAAD = []byte{}
+	}
+	return encMode.Marshal([]interface{}{"Signature1", rawProtected, externalAAD, payload})
+}
+
+// Decoded integers are int64, headers built in Go may also hold an int.
+func toInt64(value interface{}) (int64, bool) {
+	switch i := value.(type) {
+	case int64:
+		return i, true
+	case int:
+		return int64(i), true
+	}
+	return 0, false
+}
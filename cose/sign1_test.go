@@ -0,0 +1,224 @@
+package cose
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/kochavalabs/crypto"
+)
+
+// The RFC 8032 test 1 key.
+const ed25519Seed = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+
+const p256PrivHex = "25590b07bb236b0cdc4052550093684efe4e8123291c11095e1360203c0b1a63"
+const p256PubHex = "0e609d4eea6ecac33fd083bf108e90db5a31fbf9239bc5cc19a8a6dd10b61050c746f61b03ab399bcc5d18bd33953b4e73a4fdf7529f58747304a32c4814d24e"
+
+// Messages created with github.com/veraison/go-cose, the EdDSA one with
+// external AAD "aad".
+const eddsaSign1Hex = "d28443a10127a10442313154546869732069732074686520636f6e74656e742e5840ef315797bdc1f059450330b98f3b7e00fb42ff0f40303fd2e0b7eae49ab7008a41fd771af6a9d3a0fa5550de428cacc80254d5aaf5b3351e5ce621d95ab21809"
+const es256Sign1Hex = "d2844fa20126036a746578742f706c61696ea104447032353654546869732069732074686520636f6e74656e742e584031a285776add05f0635b7885f0e8e5868c71fd4ea60eeecb1f3ecce28ee19d608747f95efe658a143ea5ccc9462e078574fce9bcaf26040cf901406b45dd4da0"
+
+var testPayload = []byte("This is the content.")
+
+func mustHex(s string) []byte {
+	decoded, err := crypto.FromHex(s)
+	if err != nil {
+		panic(err)
+	}
+	return decoded
+}
+
+func newEd25519Pair(t *testing.T) (crypto.Signer, crypto.Verifier) {
+	pub, priv, _ := crypto.Ed25519KeyPairFromSeed(mustHex(ed25519Seed))
+	return newTestPair(t, "ed25519", priv, pub)
+}
+
+func newP256Pair(t *testing.T) (crypto.Signer, crypto.Verifier) {
+	return newTestPair(t, "ecdsa_P256_sha-256_rfc6979", mustHex(p256PrivHex), mustHex(p256PubHex))
+}
+
+func newTestPair(t *testing.T, suiteType string, priv []byte, pub []byte) (crypto.Signer, crypto.Verifier) {
+	signer, err := crypto.NewSignerForSuite(suiteType, priv)
+	if err != nil {
+		t.Fatalf("Error creating the signer: %s", err)
+	}
+	verifier, err := crypto.NewVerifierForSuite(signer.SuiteType(), pub)
+	if err != nil {
+		t.Fatalf("Error creating the verifier: %s", err)
+	}
+	return signer, verifier
+}
+
+func TestSign1Vector(t *testing.T) {
+	signer, verifier := newEd25519Pair(t)
+	signed, err := Sign1(signer, nil, Header{HeaderKeyID: []byte("11")}, testPayload, []byte("aad"))
+	if err != nil {
+		t.Fatalf("Error signing: %s", err)
+	}
+	if crypto.ToHex(signed) != eddsaSign1Hex {
+		t.Errorf("Got %x, expected %s", signed, eddsaSign1Hex)
+	}
+	message, err := VerifySign1(verifier, mustHex(eddsaSign1Hex), []byte("aad"))
+	if err != nil {
+		t.Fatalf("Error verifying: %s", err)
+	}
+	if !bytes.Equal(message.Payload, testPayload) {
+		t.Errorf("Got %s, expected %s", message.Payload, testPayload)
+	}
+	if kid, _ := message.Unprotected[HeaderKeyID].([]byte); string(kid) != "11" {
+		t.Errorf("Got %v, expected kid 11", message.Unprotected[HeaderKeyID])
+	}
+}
+
+func TestVerifySign1ES256(t *testing.T) {
+	_, verifier := newP256Pair(t)
+	message, err := VerifySign1(verifier, mustHex(es256Sign1Hex), nil)
+	if err != nil {
+		t.Fatalf("Error verifying: %s", err)
+	}
+	if message.Protected[HeaderContentType] != "text/plain" {
+		t.Errorf("Got %v, expected text/plain", message.Protected[HeaderContentType])
+	}
+	if alg := message.Protected[HeaderAlgorithm]; alg != AlgorithmES256 {
+		t.Errorf("Got %v, expected %d", alg, AlgorithmES256)
+	}
+}
+
+func TestSign1RoundTrip(t *testing.T) {
+	for name, newPair := range map[string]func(*testing.T) (crypto.Signer, crypto.Verifier){
+		"EdDSA": newEd25519Pair,
+		"ES256": newP256Pair,
+	} {
+		t.Run(name, func(t *testing.T) {
+			signer, verifier := newPair(t)
+			protected := Header{HeaderContentType: "application/cbor"}
+			signed, err := Sign1(signer, protected, nil, testPayload, nil)
+			if err != nil {
+				t.Fatalf("Error signing: %s", err)
+			}
+			if _, ok := protected[HeaderAlgorithm]; ok {
+				t.Error("Expected the protected header not to be modified.")
+			}
+			message, err := VerifySign1(verifier, signed, nil)
+			if err != nil {
+				t.Fatalf("Error verifying: %s", err)
+			}
+			if !bytes.Equal(message.Payload, testPayload) {
+				t.Errorf("Got %s, expected %s", message.Payload, testPayload)
+			}
+			// Untagged messages are accepted too.
+			if _, err := VerifySign1(verifier, signed[1:], nil); err != nil {
+				t.Errorf("Error verifying the untagged message: %s", err)
+			}
+		})
+	}
+}
+
+func TestSign1Errors(t *testing.T) {
+	signer, _ := newEd25519Pair(t)
+	if _, err := Sign1(signer, Header{HeaderAlgorithm: AlgorithmES256}, nil, testPayload, nil); !errors.Is(err, ErrAlgorithm) {
+		t.Errorf("Got %v, expected %v", err, ErrAlgorithm)
+	}
+	if _, err := Sign1(signer, Header{HeaderCritical: []int64{HeaderContentType}}, nil, testPayload, nil); !errors.Is(err, ErrCritical) {
+		t.Errorf("Got %v, expected %v", err, ErrCritical)
+	}
+	if _, err := Sign1(signer, nil, Header{HeaderAlgorithm: AlgorithmEdDSA}, testPayload, nil); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Got %v, expected %v", err, ErrMalformed)
+	}
+	k1Signer, err := crypto.NewSecp256k1Sha_256Signer(mustHex(p256PrivHex))
+	if err != nil {
+		t.Fatalf("Error creating the signer: %s", err)
+	}
+	if _, err := Sign1(k1Signer, nil, nil, testPayload, nil); !errors.Is(err, ErrAlgorithm) {
+		t.Errorf("Got %v, expected %v", err, ErrAlgorithm)
+	}
+}
+
+// Builds a tagged COSE_Sign1 with the given protected header, signed by
+// signer when it is not nil.
+func buildSign1(t *testing.T, signer crypto.Signer, protected interface{}, unprotected Header) []byte {
+	rawProtected, err := encMode.Marshal(protected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signature := []byte{1, 2, 3}
+	if signer != nil {
+		toSign, _ := sigStructure(rawProtected, nil, testPayload)
+		signature, _ = signer.Sign(toSign)
+	}
+	if unprotected == nil {
+		unprotected = Header{}
+	}
+	signed, err := encMode.Marshal(cbor.Tag{Number: sign1Tag, Content: sign1Message{
+		Protected:   rawProtected,
+		Unprotected: unprotected,
+		Payload:     testPayload,
+		Signature:   signature,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestVerifySign1Errors(t *testing.T) {
+	signer, verifier := newEd25519Pair(t)
+	_, p256Verifier := newP256Pair(t)
+	valid := mustHex(eddsaSign1Hex)
+	tampered := append([]byte{}, valid...)
+	tampered[len(tampered)-1] ^= 1
+	duplicateKey := mustHex("d28443a10127a20442313104423131" + eddsaSign1Hex[30:])
+
+	testCases := []struct {
+		name     string
+		verifier crypto.Verifier
+		data     []byte
+		aad      []byte
+		expected error
+	}{
+		{"wrong aad", verifier, valid, []byte("bad"), ErrSignature},
+		{"no aad", verifier, valid, nil, ErrSignature},
+		{"tampered", verifier, tampered, []byte("aad"), ErrSignature},
+		{"wrong algorithm", p256Verifier, valid, []byte("aad"), ErrAlgorithm},
+		{"truncated", verifier, valid[:len(valid)-1], []byte("aad"), ErrMalformed},
+		{"empty", verifier, nil, nil, ErrMalformed},
+		{"not an array", verifier, []byte{0xd2, 0xa0}, nil, ErrMalformed},
+		{"duplicate key", verifier, duplicateKey, []byte("aad"), ErrMalformed},
+		{"missing alg", verifier, buildSign1(t, signer, Header{HeaderContentType: "text/plain"}, nil), nil, ErrMalformed},
+		{"text alg", verifier, buildSign1(t, signer, Header{HeaderAlgorithm: "EdDSA"}, nil), nil, ErrAlgorithm},
+		{"text label", verifier, buildSign1(t, signer, map[interface{}]interface{}{int64(1): AlgorithmEdDSA, "x": 1}, nil), nil, ErrMalformed},
+		{
+			"critical",
+			verifier,
+			buildSign1(t, signer, Header{HeaderAlgorithm: AlgorithmEdDSA, HeaderCritical: []int64{HeaderContentType}}, nil),
+			nil,
+			ErrCritical,
+		},
+		{
+			"unprotected alg",
+			verifier,
+			buildSign1(t, signer, Header{HeaderContentType: "text/plain"}, Header{HeaderAlgorithm: AlgorithmEdDSA}),
+			nil,
+			ErrMalformed,
+		},
+		{
+			"repeated label",
+			verifier,
+			buildSign1(t, signer, Header{HeaderAlgorithm: AlgorithmEdDSA, HeaderKeyID: []byte("1")}, Header{HeaderKeyID: []byte("1")}),
+			nil,
+			ErrMalformed,
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := VerifySign1(tc.verifier, tc.data, tc.aad); !errors.Is(err, tc.expected) {
+				t.Errorf("Got %v, expected %v", err, tc.expected)
+			}
+		})
+	}
+	if _, err := VerifySign1(verifier, buildSign1(t, signer, Header{HeaderAlgorithm: AlgorithmEdDSA}, nil), nil); err != nil {
+		t.Errorf("Error verifying: %s", err)
+	}
+}
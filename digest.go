@@ -0,0 +1,61 @@
+package crypto
+
+import (
+	"fmt"
+	"hash"
+)
+
+// DigestSigner is implemented by signers of suites that sign a hash of the
+// message, so the hash can be computed somewhere else. The ECDSA and RSA
+// signers and the Ed25519ph signer implement it, e.g.
+//
+//	signer, _ := NewP256Sha_256RFC6979Signer(privKey)
+//	signature, err := signer.(DigestSigner).SignDigest(digest)
+type DigestSigner interface {
+	Signer
+	// SignDigest signs a digest computed with the suite's hash. The signature
+	// is the same as Sign creates over the message that was hashed.
+	SignDigest(digest []byte) ([]byte, error)
+}
+
+// DigestVerifier is implemented by verifiers of suites that verify a hash of
+// the message, see DigestSigner.
+type DigestVerifier interface {
+	Verifier
+	// VerifyDigest verifies a signature over a digest computed with the
+	// suite's hash. Returns false if the digest has the wrong length.
+	VerifyDigest(digest []byte, signature []byte) bool
+}
+
+// Checks that digest has the output length of the suite's hash.
+func checkDigestLength(s interface {
+	Suite
+	newHash() hash.Hash
+}, digest []byte) error {
+	hashFunc := s.newHash()
+	if hashFunc == nil {
+		return fmt.Errorf("%w: %s", ErrSuiteCannotPrehash, s.SuiteType())
+	}
+	if len(digest) != hashFunc.Size() {
+		return fmt.Errorf(
+			"%w: expected %d bytes, got %d bytes",
+			ErrDigestLength, hashFunc.Size(), len(digest))
+	}
+	return nil
+}
+
+// Convenience function for implementing DigestSigner.
+func signCheckedDigest(s digestSigner, digest []byte) ([]byte, error) {
+	if err := checkDigestLength(s, digest); err != nil {
+		return nil, err
+	}
+	return s.signDigest(digest)
+}
+
+// Convenience function for implementing DigestVerifier.
+func verifyCheckedDigest(s digestVerifier, digest []byte, signature []byte) bool {
+	if checkDigestLength(s, digest) != nil {
+		return false
+	}
+	return s.verifyDigest(digest, signature) == nil
+}
@@ -0,0 +1,106 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestSignDigest(t *testing.T) {
+	message := []byte("hashed in another service")
+	for _, tt := range registryTestCases {
+		t.Run(tt.suiteType, func(t *testing.T) {
+			privKey, _ := FromHex(tt.privKeyHex)
+			pubKey, _ := FromHex(tt.pubKeyHex)
+			signer, _ := NewSignerForSuite(tt.suiteType, privKey)
+			verifier, _ := NewVerifierForSuite(tt.suiteType, pubKey)
+
+			prehashSigner, signerOk := signer.(DigestSigner)
+			prehashVerifier, verifierOk := verifier.(DigestVerifier)
+			if nonStreamingSuites[tt.suiteType] {
+				if signerOk {
+					_, err := prehashSigner.SignDigest(make([]byte, 32))
+					if !errors.Is(err, ErrSuiteCannotPrehash) {
+						t.Errorf("Expected ErrSuiteCannotPrehash, got %v", err)
+					}
+				}
+				return
+			}
+			if !signerOk || !verifierOk {
+				t.Fatalf("Expected suite to implement DigestSigner and DigestVerifier.")
+			}
+
+			hashFunc := signer.(digestSigner).newHash()
+			hashFunc.Write(message)
+			digest := hashFunc.Sum(nil)
+
+			signature, err := prehashSigner.SignDigest(digest)
+			if err != nil {
+				t.Fatalf("Error signing: %s", err)
+			}
+			if !verifier.Verify(message, signature) {
+				t.Errorf("Expected digest signature to verify over the message.")
+			}
+			signature, _ = signer.Sign(message)
+			if !prehashVerifier.VerifyDigest(digest, signature) || !prehashSigner.(DigestVerifier).VerifyDigest(digest, signature) {
+				t.Errorf("Expected signature to verify over the digest.")
+			}
+
+			_, err = prehashSigner.SignDigest(digest[1:])
+			if !errors.Is(err, ErrDigestLength) {
+				t.Errorf("Expected ErrDigestLength, got %v", err)
+			}
+			_, err = prehashSigner.SignDigest(append(digest, 0))
+			if !errors.Is(err, ErrDigestLength) {
+				t.Errorf("Expected ErrDigestLength, got %v", err)
+			}
+			if prehashVerifier.VerifyDigest(digest[1:], signature) {
+				t.Errorf("Expected short digest to not verify.")
+			}
+		})
+	}
+}
+
+func TestSignDigestSecp256k1Vector(t *testing.T) {
+	privKey, _ := FromHex(Secp256k1PrivHex)
+	signer, _ := NewSecp256k1Keccak256Signer(privKey)
+	for _, tt := range secp256k1TestCases {
+		digest := (&Keccak256Hasher{}).Hash(tt.message)
+		signature, err := signer.(DigestSigner).SignDigest(digest)
+		if err != nil {
+			t.Fatalf("Error signing: %s", err)
+		}
+		if ToHex(signature) != tt.signature {
+			t.Errorf("Expected %s, signature was %x.", tt.signature, signature)
+		}
+	}
+}
+
+func TestSignDigestDeterministic(t *testing.T) {
+	privKey, _ := FromHex(P384PrivHex)
+	signer, _ := NewP384Sha_384RFC6979Signer(privKey)
+	message := []byte{1, 2, 3}
+	fromDigest, _ := signer.(DigestSigner).SignDigest((&Sha_384Hasher{}).Hash(message))
+	signature, _ := signer.Sign(message)
+	if !bytes.Equal(fromDigest, signature) {
+		t.Errorf("Expected %x, got %x", signature, fromDigest)
+	}
+}
+
+func TestSignDigestEd25519ph(t *testing.T) {
+	tt := ed25519VariantTestCases[0]
+	signer, verifier := newEd25519VariantPair(t, tt.suiteType, tt.seed, tt.pubKey, tt.context)
+	message, _ := FromHex(tt.message)
+	digest := (&Sha_512Hasher{}).Hash(message)
+
+	signature, err := signer.(DigestSigner).SignDigest(digest)
+	if err != nil {
+		t.Fatalf("Error signing: %s", err)
+	}
+	if ToHex(signature) != tt.signature {
+		t.Errorf("Expected %s, signature was %x.", tt.signature, signature)
+	}
+	if !verifier.(DigestVerifier).VerifyDigest(digest, signature) {
+		t.Errorf("Expected signature to verify over the digest.")
+	}
+}
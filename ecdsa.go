@@ -4,9 +4,13 @@ import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
 	"crypto/rand"
+	"crypto/sha256"
 	"errors"
 	"fmt"
+	"hash"
 	"math/big"
+
+	"golang.org/x/crypto/sha3"
 )
 
 const (
@@ -15,6 +19,20 @@ const (
 	P256SignatureLength  = 64
 )
 
+func init() {
+	mustRegisterECDSASuite("ecdsa_P256_sha3-256", nil, NewP256Sha3_256Verifier)
+	mustRegisterECDSASuite("ecdsa_P256_sha3-256_det", NewP256Sha3_256DetSigner, NewP256Sha3_256Verifier)
+	mustRegisterECDSASuite("ecdsa_P256_sha3-256_indet", NewP256Sha3_256InDetSigner, NewP256Sha3_256Verifier)
+	mustRegisterECDSASuite("ecdsa_P256_sha3-256_rfc6979", NewP256Sha3_256RFC6979Signer, NewP256Sha3_256Verifier)
+	mustRegisterECDSASuite("ecdsa_P256_sha-256", nil, NewP256Sha_256Verifier)
+	mustRegisterECDSASuite("ecdsa_P256_sha-256_rfc6979", NewP256Sha_256RFC6979Signer, NewP256Sha_256Verifier)
+	mustRegisterECDSASuite("ecdsa_P256_sha-256_indet", NewP256Sha_256InDetSigner, NewP256Sha_256Verifier)
+	mustRegisterECDSASuite("ecdsa_P256_shake256", nil, NewP256Shake256Verifier)
+	mustRegisterECDSASuite("ecdsa_P256_shake256_det", NewP256Shake256DetSigner, NewP256Shake256Verifier)
+	mustRegisterECDSASuite("ecdsa_P256_shake256_indet", NewP256Shake256InDetSigner, NewP256Shake256Verifier)
+	mustRegisterECDSASuite("ecdsa_P256_shake256_rfc6979", NewP256Shake256RFC6979Signer, NewP256Shake256Verifier)
+}
+
 // GenerateKeyPairP256 create a private/public key pair using the P256 elliptic
 // curve
 func GenerateKeyPairP256() (*ecdsa.PrivateKey, *ecdsa.PublicKey, error) {
@@ -36,51 +54,213 @@ type EcdsaVerifier struct {
 	publicKey *ecdsa.PublicKey
 	hasher    Hasher
 	suiteType string
+	// When set signatures with S greater than half the group order are
+	// rejected, see NewLowSVerifier.
+	lowS bool
 }
 
 func (s *EcdsaVerifier) Verify(toVerify []byte, signature []byte) bool {
-	messageHash := s.hasher.Hash(toVerify)
-	R, S := splitByteSlice(signature)
-	return ecdsa.Verify(s.publicKey, messageHash, R, S)
+	return s.VerifyErr(toVerify, signature) == nil
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *EcdsaVerifier) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifyDigest(s.hasher.Hash(toVerify), signature)
+}
+
+func (s *EcdsaVerifier) verifyDigest(digest []byte, signature []byte) error {
+	R, S, err := DecodeEcdsaSignature(signature, ecdsaScalarLength(s.publicKey.Curve))
+	if err != nil {
+		return err
+	}
+	N := s.publicKey.Curve.Params().N
+	if R.Sign() == 0 || S.Sign() == 0 || R.Cmp(N) >= 0 || S.Cmp(N) >= 0 {
+		return fmt.Errorf("%w: R and S must be between 1 and the group order", ErrSignatureEncoding)
+	}
+	if s.lowS && isHighS(N, S) {
+		return fmt.Errorf("%w: S is not in the lower half of the order", ErrSignatureEncoding)
+	}
+	if !ecdsa.Verify(s.publicKey, digest, R, S) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+func (s *EcdsaVerifier) newHash() hash.Hash {
+	hashFunc, _ := newStreamHash(s.hasher)
+	return hashFunc
+}
+
+// VerifyDigest verifies a signature over a digest, see DigestVerifier.
+func (s *EcdsaVerifier) VerifyDigest(digest []byte, signature []byte) bool {
+	return verifyCheckedDigest(s, digest, signature)
 }
 
 func (s *EcdsaVerifier) SuiteType() string {
 	return s.suiteType
 }
 
+// PublicKey returns the public key, X || Y, see PublicKeyHolder.
+func (s *EcdsaVerifier) PublicKey() []byte {
+	return ecdsaPublicKeyBytes(s.publicKey)
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *EcdsaVerifier) KeyID() string {
+	return KeyIDFromPublicKey(s.PublicKey())
+}
+
+// NewLowSVerifier returns a copy of an ECDSA verifier, or of the verifier of
+// an ECDSA signer, that rejects signatures whose S is greater than half the
+// group order with ErrSignatureEncoding. Anyone can turn a valid signature
+// (R, S) into the equally valid (R, N - S), accepting only the low S one
+// makes signatures unique so they can be compared as bytes. The signers in
+// this package only create low S signatures, e.g.
+//
+//	verifier, _ := NewVerifierForSuite("ecdsa_P256_sha-256", pubKey)
+//	verifier, _ = NewLowSVerifier(verifier)
+//
+// ecdsa_secp256k1_keccak256 verifiers already reject high S signatures and
+// are returned as is. Returns ErrUnexpectedSuite for other suites. To get
+// such verifiers from NewVerifierForSuite see SetRequireLowS.
+func NewLowSVerifier(verifier Verifier) (Verifier, error) {
+	switch v := verifier.(type) {
+	case *EcdsaVerifier:
+		strict := *v
+		strict.lowS = true
+		return &strict, nil
+	case *EcdsaSigner:
+		return NewLowSVerifier(v.verifier)
+	case *secp256k1Verifier:
+		if v.recoverable {
+			return v, nil
+		}
+		strict := *v
+		strict.lowS = true
+		return &strict, nil
+	case *secp256k1Signer:
+		return NewLowSVerifier(v.verifier)
+	case *ethereumAddressVerifier:
+		return v, nil
+	}
+	return nil, fmt.Errorf("%w: %s is not an ECDSA suite", ErrUnexpectedSuite, verifier.SuiteType())
+}
+
+// Returns a copy of an ECDSA signer whose Verify rejects high S signatures,
+// see NewLowSVerifier. Used for suites set with SetRequireLowS.
+func newLowSSigner(signer Signer) (Signer, error) {
+	switch s := signer.(type) {
+	case *EcdsaSigner:
+		strict := *s
+		strict.verifier = &EcdsaVerifier{}
+		*strict.verifier = *s.verifier
+		strict.verifier.lowS = true
+		return &strict, nil
+	case *secp256k1Signer:
+		if s.verifier.recoverable {
+			return s, nil
+		}
+		strict := *s
+		strict.verifier = &secp256k1Verifier{}
+		*strict.verifier = *s.verifier
+		strict.verifier.lowS = true
+		return &strict, nil
+	}
+	return nil, fmt.Errorf("%w: %s is not an ECDSA suite", ErrUnexpectedSuite, signer.SuiteType())
+}
+
 type EcdsaSigner struct {
 	hasher     Hasher
 	verifier   *EcdsaVerifier
 	reader     newReader
 	privateKey ecdsa.PrivateKey
+	// When set k is chosen as described in RFC 6979 using an HMAC of this
+	// hash function, reader is not used.
+	nonceHash func() hash.Hash
 }
 
 func (s *EcdsaSigner) Sign(toSign []byte) ([]byte, error) {
-	messageHash := s.hasher.Hash(toSign)
-	reader := s.reader(s.hasher, messageHash, s.privateKey.D.Bytes())
-	R, S, err := ecdsa.Sign(reader, &s.privateKey, messageHash)
-	if err != nil {
-		return nil, err
+	return s.signDigest(s.hasher.Hash(toSign))
+}
+
+// Signatures always have a low S, (R, N - S) is as valid as (R, S) so only
+// one of the two is created.
+func (s *EcdsaSigner) signDigest(messageHash []byte) ([]byte, error) {
+	var R, S *big.Int
+	if s.nonceHash != nil {
+		R, S = signRFC6979(&s.privateKey, messageHash, s.nonceHash)
+	} else {
+		reader := s.reader(s.hasher, messageHash, s.privateKey.D.Bytes())
+		var err error
+		R, S, err = ecdsa.Sign(reader, &s.privateKey, messageHash)
+		if err != nil {
+			return nil, err
+		}
 	}
-	return append(R.Bytes(), S.Bytes()...), nil
+	N := s.privateKey.Curve.Params().N
+	if isHighS(N, S) {
+		S = new(big.Int).Sub(N, S)
+	}
+	return EncodeEcdsaSignature(R, S, ecdsaScalarLength(s.privateKey.Curve))
+}
+
+// Returns true if S is greater than half of the group order N.
+func isHighS(N *big.Int, S *big.Int) bool {
+	return S.Cmp(new(big.Int).Rsh(N, 1)) > 0
 }
 
 func (s *EcdsaSigner) Verify(toVerify []byte, signature []byte) bool {
 	return s.verifier.Verify(toVerify, signature)
 }
 
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *EcdsaSigner) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifier.VerifyErr(toVerify, signature)
+}
+
+func (s *EcdsaSigner) verifyDigest(digest []byte, signature []byte) error {
+	return s.verifier.verifyDigest(digest, signature)
+}
+
+func (s *EcdsaSigner) newHash() hash.Hash {
+	return s.verifier.newHash()
+}
+
+// SignDigest signs a digest computed with the suite's hash, see DigestSigner.
+func (s *EcdsaSigner) SignDigest(digest []byte) ([]byte, error) {
+	return signCheckedDigest(s, digest)
+}
+
+// VerifyDigest verifies a signature over a digest, see DigestVerifier.
+func (s *EcdsaSigner) VerifyDigest(digest []byte, signature []byte) bool {
+	return verifyCheckedDigest(s, digest, signature)
+}
+
 func (s *EcdsaSigner) SuiteType() string {
 	return s.verifier.SuiteType()
 }
 
-// Convenience function for creating verifiers. Used in functions such as
-// NewP256Sha3_256DetSigner
+// PublicKey returns the public key, see PublicKeyHolder.
+func (s *EcdsaSigner) PublicKey() []byte {
+	return s.verifier.PublicKey()
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *EcdsaSigner) KeyID() string {
+	return s.verifier.KeyID()
+}
+
+// Convenience function for creating verifiers, pubData is checked with
+// checkEcdsaPublicKey. Used in functions such as NewP256Sha3_256Verifier
 func getVerifier(
 	curve elliptic.Curve,
 	pubData []byte,
 	hasher Hasher,
 	suiteType string,
-) *EcdsaVerifier {
+) (*EcdsaVerifier, error) {
+	if err := checkEcdsaPublicKey(curve, pubData); err != nil {
+		return nil, err
+	}
 	X, Y := splitByteSlice(pubData)
 	pubKey := ecdsa.PublicKey{
 		X:     X,
@@ -91,120 +271,231 @@ func getVerifier(
 		hasher:    hasher,
 		publicKey: &pubKey,
 		suiteType: suiteType,
-	}
+	}, nil
 }
 
-// Convenience function for creating signers. Used in functions such as
-// NewP256Sha3_256DetSigner
+// Convenience function for creating signers, privData is checked with
+// checkEcdsaPrivateKey. Used in functions such as NewP256Sha3_256DetSigner
 func getSigner(
 	curve elliptic.Curve,
 	privData []byte,
 	hasher Hasher,
 	reader newReader,
 	suiteType string,
-) *EcdsaSigner {
+) (*EcdsaSigner, error) {
+	if err := checkEcdsaPrivateKey(curve, privData); err != nil {
+		return nil, err
+	}
 	k := new(big.Int).SetBytes(privData)
 	privKey := new(ecdsa.PrivateKey)
 	privKey.PublicKey.Curve = curve
 	privKey.D = k
 	privKey.PublicKey.X, privKey.PublicKey.Y = curve.ScalarBaseMult(k.Bytes())
-	pubData := append(privKey.PublicKey.X.Bytes(), privKey.PublicKey.Y.Bytes()...)
 	return &EcdsaSigner{
 		hasher:     hasher,
 		reader:     reader,
 		privateKey: *privKey,
-		verifier:   getVerifier(curve, pubData, hasher, suiteType),
+		verifier: &EcdsaVerifier{
+			hasher:    hasher,
+			publicKey: &privKey.PublicKey,
+			suiteType: suiteType,
+		},
+	}, nil
+}
+
+// Checks that pubData is the X || Y encoding of a point on curve.
+func checkEcdsaPublicKey(curve elliptic.Curve, pubData []byte) error {
+	expected := 2 * ((curve.Params().BitSize + 7) / 8)
+	if len(pubData) != expected {
+		return fmt.Errorf(
+			"%w: Bad keydata passed to verifier, expected %d bytes, got %d bytes",
+			ErrInvalidPublicKey, expected, len(pubData))
+	}
+	X, Y := splitByteSlice(pubData)
+	if !curve.IsOnCurve(X, Y) {
+		return fmt.Errorf("%w: Bad keydata passed to verifier, point is not on the curve", ErrInvalidPublicKey)
 	}
+	return nil
 }
 
-func NewP256Sha3_256Verifier(pubData []byte) (Verifier, error) {
-	if len(pubData) != 64 {
-		errorMsg := fmt.Sprintf(
-			"Bad keydata passed to verifier, expected 64 bytes, got %d bytes",
-			len(pubData))
-		return nil, errors.New(errorMsg)
+// Checks that privData is a scalar in the range [1, N-1] for curve.
+func checkEcdsaPrivateKey(curve elliptic.Curve, privData []byte) error {
+	expected := ecdsaScalarLength(curve)
+	if len(privData) != expected {
+		return fmt.Errorf(
+			"Bad keydata passed to signer, expected %d bytes, got %d bytes",
+			expected, len(privData))
+	}
+	k := new(big.Int).SetBytes(privData)
+	if k.Sign() == 0 || k.Cmp(curve.Params().N) >= 0 {
+		return errors.New("Bad keydata passed to signer, key is out of range")
 	}
-	return getVerifier(
+	return nil
+}
+
+// Convenience function for creating validated verifiers. Used in functions
+// such as NewP384Sha_384Verifier
+func newEcdsaVerifier(
+	curve elliptic.Curve,
+	pubData []byte,
+	hasher Hasher,
+	suiteType string,
+) (Verifier, error) {
+	verifier, err := getVerifier(curve, pubData, hasher, suiteType)
+	if err != nil {
+		return nil, err
+	}
+	return verifier, nil
+}
+
+// Convenience function for creating validated signers. Used in functions such
+// as NewP384Sha_384RFC6979Signer. When nonceHash is nil k is random, otherwise
+// it is chosen as described in RFC 6979 with an HMAC of nonceHash.
+func newEcdsaSigner(
+	curve elliptic.Curve,
+	privData []byte,
+	hasher Hasher,
+	nonceHash func() hash.Hash,
+	suiteType string,
+) (Signer, error) {
+	signer, err := getSigner(curve, privData, hasher, newRandomReader, suiteType)
+	if err != nil {
+		return nil, err
+	}
+	signer.nonceHash = nonceHash
+	return signer, nil
+}
+
+func NewP256Sha3_256Verifier(pubData []byte) (Verifier, error) {
+	return newEcdsaVerifier(
 		elliptic.P256(), pubData, &Sha3_256Hasher{}, "ecdsa_P256_sha3-256",
-	), nil
+	)
 }
 
 func NewP256Sha3_256DetSigner(privData []byte) (Signer, error) {
-	if len(privData) != 32 {
-		errorMsg := fmt.Sprintf(
-			"Bad keydata passed to verifier, expected 32 bytes, got %d bytes",
-			len(privData))
-		return nil, errors.New(errorMsg)
-	}
 	hasher := &Sha3_256Hasher{}
-	return getSigner(
+	signer, err := getSigner(
 		elliptic.P256(),
 		privData,
 		hasher,
 		newDeterministicReader,
 		"ecdsa_P256_sha3-256_det",
-	), nil
+	)
+	if err != nil {
+		return nil, err
+	}
+	return signer, nil
 }
 
-func NewP256Sha3_256InDetSigner(privData []byte) (Signer, error) {
-	if len(privData) != 32 {
-		errorMsg := fmt.Sprintf(
-			"Bad keydata passed to verifier, expected 32 bytes, got %d bytes",
-			len(privData))
-		return nil, errors.New(errorMsg)
+// NewP256Sha3_256RFC6979Signer creates a deterministic signer whose k is
+// generated as described in RFC 6979, using HMAC-SHA3-256.
+func NewP256Sha3_256RFC6979Signer(privData []byte) (Signer, error) {
+	signer, err := getSigner(
+		elliptic.P256(),
+		privData,
+		&Sha3_256Hasher{},
+		nil,
+		"ecdsa_P256_sha3-256_rfc6979",
+	)
+	if err != nil {
+		return nil, err
 	}
+	signer.nonceHash = sha3.New256
+	return signer, nil
+}
+
+func NewP256Sha3_256InDetSigner(privData []byte) (Signer, error) {
 	hasher := &Sha3_256Hasher{}
-	return getSigner(
+	signer, err := getSigner(
 		elliptic.P256(),
 		privData,
 		hasher,
 		newRandomReader,
 		"ecdsa_P256_sha3-256_indet",
-	), nil
+	)
+	if err != nil {
+		return nil, err
+	}
+	return signer, nil
 }
 
 func NewP256Shake256Verifier(pubData []byte) (Verifier, error) {
-	if len(pubData) != 64 {
-		errorMsg := fmt.Sprintf(
-			"Bad keydata passed to verifier, expected 64 bytes, got %d bytes",
-			len(pubData))
-		return nil, errors.New(errorMsg)
-	}
-	return getVerifier(
+	return newEcdsaVerifier(
 		elliptic.P256(), pubData, &Shake256Hasher{}, "ecdsa_P256_shake256",
-	), nil
+	)
 }
 
 func NewP256Shake256DetSigner(privData []byte) (Signer, error) {
-	if len(privData) != 32 {
-		errorMsg := fmt.Sprintf(
-			"Bad keydata passed to verifier, expected 32 bytes, got %d bytes",
-			len(privData))
-		return nil, errors.New(errorMsg)
-	}
 	hasher := &Shake256Hasher{}
-	return getSigner(
+	signer, err := getSigner(
 		elliptic.P256(),
 		privData,
 		hasher,
 		newDeterministicReader,
 		"ecdsa_P256_shake256_det",
-	), nil
+	)
+	if err != nil {
+		return nil, err
+	}
+	return signer, nil
 }
 
-func NewP256Shake256InDetSigner(privData []byte) (Signer, error) {
-	if len(privData) != 32 {
-		errorMsg := fmt.Sprintf(
-			"Bad keydata passed to verifier, expected 32 bytes, got %d bytes",
-			len(privData))
-		return nil, errors.New(errorMsg)
+// NewP256Shake256RFC6979Signer creates a deterministic signer whose k is
+// generated as described in RFC 6979, using an HMAC of SHAKE256 with a 256bit
+// output.
+func NewP256Shake256RFC6979Signer(privData []byte) (Signer, error) {
+	signer, err := getSigner(
+		elliptic.P256(),
+		privData,
+		&Shake256Hasher{},
+		nil,
+		"ecdsa_P256_shake256_rfc6979",
+	)
+	if err != nil {
+		return nil, err
 	}
+	signer.nonceHash = newShake256Hash
+	return signer, nil
+}
+
+func NewP256Shake256InDetSigner(privData []byte) (Signer, error) {
 	hasher := &Shake256Hasher{}
-	return getSigner(
+	signer, err := getSigner(
 		elliptic.P256(),
 		privData,
 		hasher,
 		newRandomReader,
 		"ecdsa_P256_shake256_indet",
-	), nil
+	)
+	if err != nil {
+		return nil, err
+	}
+	return signer, nil
+}
+
+// NewP256Sha_256Verifier constructor for a P256 Verifier hashing with SHA-256.
+// This is the ES256 algorithm used by JOSE, WebCrypto and most cloud KMS
+// offerings.
+func NewP256Sha_256Verifier(pubData []byte) (Verifier, error) {
+	return newEcdsaVerifier(
+		elliptic.P256(), pubData, &Sha_256Hasher{}, "ecdsa_P256_sha-256",
+	)
+}
+
+// NewP256Sha_256RFC6979Signer constructor for a deterministic P256 Signer
+// hashing with SHA-256, k is chosen as described in RFC 6979.
+func NewP256Sha_256RFC6979Signer(privData []byte) (Signer, error) {
+	return newEcdsaSigner(
+		elliptic.P256(), privData, &Sha_256Hasher{}, sha256.New,
+		"ecdsa_P256_sha-256_rfc6979",
+	)
+}
+
+// NewP256Sha_256InDetSigner constructor for an indeterministic P256 Signer
+// hashing with SHA-256.
+func NewP256Sha_256InDetSigner(privData []byte) (Signer, error) {
+	return newEcdsaSigner(
+		elliptic.P256(), privData, &Sha_256Hasher{}, nil,
+		"ecdsa_P256_sha-256_indet",
+	)
 }
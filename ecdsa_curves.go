@@ -0,0 +1,131 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+
+	"golang.org/x/crypto/sha3"
+)
+
+// Suites for the larger NIST curves, P384 and P521. Each is paired with a hash
+// of matching strength. The deterministic signers pick k as described in
+// RFC 6979, the indeterministic signers use a random k.
+const (
+	P384PrivateKeyLength = 48
+	P384PublicKeyLength  = 96
+	P384SignatureLength  = 96
+
+	P521PrivateKeyLength = 66
+	P521PublicKeyLength  = 132
+	P521SignatureLength  = 132
+)
+
+func init() {
+	mustRegisterECDSASuite("ecdsa_P384_sha-384", nil, NewP384Sha_384Verifier)
+	mustRegisterECDSASuite("ecdsa_P384_sha-384_rfc6979", NewP384Sha_384RFC6979Signer, NewP384Sha_384Verifier)
+	mustRegisterECDSASuite("ecdsa_P384_sha-384_indet", NewP384Sha_384InDetSigner, NewP384Sha_384Verifier)
+	mustRegisterECDSASuite("ecdsa_P521_sha-512", nil, NewP521Sha_512Verifier)
+	mustRegisterECDSASuite("ecdsa_P521_sha-512_rfc6979", NewP521Sha_512RFC6979Signer, NewP521Sha_512Verifier)
+	mustRegisterECDSASuite("ecdsa_P521_sha-512_indet", NewP521Sha_512InDetSigner, NewP521Sha_512Verifier)
+	mustRegisterECDSASuite("ecdsa_P521_sha3-512", nil, NewP521Sha3_512Verifier)
+	mustRegisterECDSASuite("ecdsa_P521_sha3-512_rfc6979", NewP521Sha3_512RFC6979Signer, NewP521Sha3_512Verifier)
+	mustRegisterECDSASuite("ecdsa_P521_sha3-512_indet", NewP521Sha3_512InDetSigner, NewP521Sha3_512Verifier)
+}
+
+// GenerateKeyPairP384 create a private/public key pair using the P384 elliptic
+// curve
+func GenerateKeyPairP384() (*ecdsa.PrivateKey, *ecdsa.PublicKey, error) {
+	prvKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prvKey, &prvKey.PublicKey, nil
+}
+
+// GenerateKeyPairP521 create a private/public key pair using the P521 elliptic
+// curve
+func GenerateKeyPairP521() (*ecdsa.PrivateKey, *ecdsa.PublicKey, error) {
+	prvKey, err := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prvKey, &prvKey.PublicKey, nil
+}
+
+// NewP384Sha_384Verifier constructor for a P384 Verifier hashing with SHA-384
+func NewP384Sha_384Verifier(pubData []byte) (Verifier, error) {
+	return newEcdsaVerifier(
+		elliptic.P384(), pubData, &Sha_384Hasher{}, "ecdsa_P384_sha-384",
+	)
+}
+
+// NewP384Sha_384RFC6979Signer constructor for a deterministic P384 Signer
+// hashing with SHA-384
+func NewP384Sha_384RFC6979Signer(privData []byte) (Signer, error) {
+	return newEcdsaSigner(
+		elliptic.P384(), privData, &Sha_384Hasher{}, sha512.New384,
+		"ecdsa_P384_sha-384_rfc6979",
+	)
+}
+
+// NewP384Sha_384InDetSigner constructor for an indeterministic P384 Signer
+// hashing with SHA-384
+func NewP384Sha_384InDetSigner(privData []byte) (Signer, error) {
+	return newEcdsaSigner(
+		elliptic.P384(), privData, &Sha_384Hasher{}, nil,
+		"ecdsa_P384_sha-384_indet",
+	)
+}
+
+// NewP521Sha_512Verifier constructor for a P521 Verifier hashing with SHA-512
+func NewP521Sha_512Verifier(pubData []byte) (Verifier, error) {
+	return newEcdsaVerifier(
+		elliptic.P521(), pubData, &Sha_512Hasher{}, "ecdsa_P521_sha-512",
+	)
+}
+
+// NewP521Sha_512RFC6979Signer constructor for a deterministic P521 Signer
+// hashing with SHA-512
+func NewP521Sha_512RFC6979Signer(privData []byte) (Signer, error) {
+	return newEcdsaSigner(
+		elliptic.P521(), privData, &Sha_512Hasher{}, sha512.New,
+		"ecdsa_P521_sha-512_rfc6979",
+	)
+}
+
+// NewP521Sha_512InDetSigner constructor for an indeterministic P521 Signer
+// hashing with SHA-512
+func NewP521Sha_512InDetSigner(privData []byte) (Signer, error) {
+	return newEcdsaSigner(
+		elliptic.P521(), privData, &Sha_512Hasher{}, nil,
+		"ecdsa_P521_sha-512_indet",
+	)
+}
+
+// NewP521Sha3_512Verifier constructor for a P521 Verifier hashing with
+// SHA3-512
+func NewP521Sha3_512Verifier(pubData []byte) (Verifier, error) {
+	return newEcdsaVerifier(
+		elliptic.P521(), pubData, &Sha3_512Hasher{}, "ecdsa_P521_sha3-512",
+	)
+}
+
+// NewP521Sha3_512RFC6979Signer constructor for a deterministic P521 Signer
+// hashing with SHA3-512
+func NewP521Sha3_512RFC6979Signer(privData []byte) (Signer, error) {
+	return newEcdsaSigner(
+		elliptic.P521(), privData, &Sha3_512Hasher{}, sha3.New512,
+		"ecdsa_P521_sha3-512_rfc6979",
+	)
+}
+
+// NewP521Sha3_512InDetSigner constructor for an indeterministic P521 Signer
+// hashing with SHA3-512
+func NewP521Sha3_512InDetSigner(privData []byte) (Signer, error) {
+	return newEcdsaSigner(
+		elliptic.P521(), privData, &Sha3_512Hasher{}, nil,
+		"ecdsa_P521_sha3-512_indet",
+	)
+}
@@ -0,0 +1,142 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"fmt"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+// Keys from the RFC 6979 appendix A.2.6 and A.2.7 test vectors.
+const P384PrivHex = "6b9d3dad2e1b8c1c05b19875b6659f4de23c3b667bf297ba9aa47740787137d896d5724e4c70a825f872c9ea60d2edf5"
+const P384PubHex = "ec3a4e415b4e19a4568618029f427fa5da9a8bc4ae92e02e06aae5286b300c64def8f0ea9055866064a254515480bc13" +
+	"8015d9b72d7d57244ea8ef9ac0c621896708a59367f9dfb9f54ca84b3f1c9db1288b231c3ae0d4fe7344fd2533264720"
+const P521PrivHex = "00fad06daa62ba3b25d2fb40133da757205de67f5bb0018fee8c86e1b68c7e75caa896eb32f1f47c70855836a6d16fcc1466f6d8fbec67db89ec0c08b0e996b83538"
+const P521PubHex = "01894550d0785932e00eaa23b694f213f8c3121f86dc97a04e5a7167db4e5bcd371123d46e45db6b5d5370a7f20fb633155d38ffa16d2bd761dcac474b9a2f5023a4" +
+	"00493101c962cd4d2fddf782285e64584139c2f91b47f87ff82354d6630f746a28a0db25741b5b34a828008b22acc23f924faafbd4d33f81ea66956dfeaa2bfdfcf5"
+
+var curveConstructorTestCases = []struct {
+	signerNew     func([]byte) (Signer, error)
+	verifierNew   func([]byte) (Verifier, error)
+	pubKeyHex     string
+	privKeyHex    string
+	sigLength     int
+	deterministic bool
+}{
+	{NewP384Sha_384RFC6979Signer, NewP384Sha_384Verifier, P384PubHex, P384PrivHex, P384SignatureLength, true},
+	{NewP384Sha_384InDetSigner, NewP384Sha_384Verifier, P384PubHex, P384PrivHex, P384SignatureLength, false},
+	{NewP521Sha_512RFC6979Signer, NewP521Sha_512Verifier, P521PubHex, P521PrivHex, P521SignatureLength, true},
+	{NewP521Sha_512InDetSigner, NewP521Sha_512Verifier, P521PubHex, P521PrivHex, P521SignatureLength, false},
+	{NewP521Sha3_512RFC6979Signer, NewP521Sha3_512Verifier, P521PubHex, P521PrivHex, P521SignatureLength, true},
+	{NewP521Sha3_512InDetSigner, NewP521Sha3_512Verifier, P521PubHex, P521PrivHex, P521SignatureLength, false},
+}
+
+func TestEcdsaCurveConstructorPairSuccess(t *testing.T) {
+	for _, tt := range curveConstructorTestCases {
+		for _, message := range [][]byte{nil, {1, 2}} {
+			privKey, _ := FromHex(tt.privKeyHex)
+			pubKey, _ := FromHex(tt.pubKeyHex)
+			signer, errConSign := tt.signerNew(privKey)
+			if errConSign != nil {
+				t.Fatalf("Error creating the signer: %s", errConSign)
+			}
+			verifier, errConVer := tt.verifierNew(pubKey)
+			if errConVer != nil {
+				t.Fatalf("Error creating the verifier: %s", errConVer)
+			}
+			testName := fmt.Sprintf("%s_%s", signer.SuiteType(), ToHex(message))
+			t.Run(testName, func(t *testing.T) {
+				signature1, _ := signer.Sign(message)
+				signature2, _ := signer.Sign(message)
+
+				if len(signature1) != tt.sigLength {
+					t.Errorf("Expected %d byte signature, got %d", tt.sigLength, len(signature1))
+				}
+				if !verifier.Verify(message, signature1) || !verifier.Verify(message, signature2) {
+					t.Errorf("Verifier didn't verify signature for signer.")
+				}
+				if verifier.Verify(append(message, 1), signature1) {
+					t.Errorf("Expected signature over another message to not verify.")
+				}
+				if reflect.DeepEqual(signature1, signature2) != tt.deterministic {
+					t.Errorf("Signature determinisim was not what was expected.")
+				}
+			})
+		}
+	}
+}
+
+// The standard library signs deterministically with RFC 6979 when no random
+// source is given, the signatures must match once S is normalized.
+func TestEcdsaCurveRFC6979MatchesStdlib(t *testing.T) {
+	testCases := []struct {
+		signerNew  func([]byte) (Signer, error)
+		curve      elliptic.Curve
+		hasher     Hasher
+		hash       crypto.Hash
+		privKeyHex string
+	}{
+		{NewP384Sha_384RFC6979Signer, elliptic.P384(), &Sha_384Hasher{}, crypto.SHA384, P384PrivHex},
+		{NewP521Sha_512RFC6979Signer, elliptic.P521(), &Sha_512Hasher{}, crypto.SHA512, P521PrivHex},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.curve.Params().Name, func(t *testing.T) {
+			privKey, _ := FromHex(tt.privKeyHex)
+			signer, _ := tt.signerNew(privKey)
+			stdKey := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(privKey)}
+			stdKey.Curve = tt.curve
+			stdKey.X, stdKey.Y = tt.curve.ScalarBaseMult(privKey)
+
+			for _, message := range []string{"sample", "test"} {
+				signature, _ := signer.Sign([]byte(message))
+				der, err := stdKey.Sign(nil, tt.hasher.Hash([]byte(message)), tt.hash)
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				expected, _ := EcdsaSignatureFromASN1(der, len(signature)/2)
+				expected = lowSSignature(tt.curve, expected)
+				if !reflect.DeepEqual(signature, expected) {
+					t.Errorf("Expected %x, signature was %x.", expected, signature)
+				}
+			}
+		})
+	}
+}
+
+func TestEcdsaCurveKeyValidation(t *testing.T) {
+	p384Pub, _ := FromHex(P384PubHex)
+	offCurve := append([]byte{}, p384Pub...)
+	offCurve[len(offCurve)-1] ^= 1
+	if _, err := NewP384Sha_384Verifier(offCurve); err == nil {
+		t.Errorf("Expected error for point not on the curve.")
+	}
+	if _, err := NewP384Sha_384Verifier(p384Pub[1:]); err == nil {
+		t.Errorf("Expected error for short public key.")
+	}
+
+	p521Priv, _ := FromHex(P521PrivHex)
+	if _, err := NewP521Sha_512RFC6979Signer(p521Priv[1:]); err == nil {
+		t.Errorf("Expected error for short private key.")
+	}
+	if _, err := NewP521Sha_512RFC6979Signer(make([]byte, P521PrivateKeyLength)); err == nil {
+		t.Errorf("Expected error for zero private key.")
+	}
+	order := elliptic.P384().Params().N.Bytes()
+	if _, err := NewP384Sha_384InDetSigner(order); err == nil {
+		t.Errorf("Expected error for private key equal to the curve order.")
+	}
+}
+
+func TestGenerateKeyPairP384P521(t *testing.T) {
+	prv384, pub384, err := GenerateKeyPairP384()
+	if err != nil || prv384.Curve != elliptic.P384() || pub384 != &prv384.PublicKey {
+		t.Errorf("Unexpected P384 key pair: %v", err)
+	}
+	prv521, pub521, err := GenerateKeyPairP521()
+	if err != nil || prv521.Curve != elliptic.P521() || pub521 != &prv521.PublicKey {
+		t.Errorf("Unexpected P521 key pair: %v", err)
+	}
+}
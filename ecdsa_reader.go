@@ -20,6 +20,10 @@ import (
 // This file contains some private helper functions for creating an io.Reader
 // that will give either a random k or a determinist k based on the message
 // and private key doing the signing.
+//
+// The deterministic k here is not a standard construction, signers that need
+// signatures reproducible by other libraries should use RFC 6979 nonces, see
+// rfc6979.go.
 
 // Gets a reader given private key data and the hash of a message to be signed.
 type newReader func(hasher Hasher, messageHash []byte, privKey []byte) io.Reader
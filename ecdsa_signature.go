@@ -0,0 +1,103 @@
+package crypto
+
+import (
+	"crypto/elliptic"
+	"encoding/asn1"
+	"fmt"
+	"math/big"
+)
+
+// Ecdsa signatures are a pair of integers (R, S). This package encodes them
+// as a fixed width R||S, where both integers are left padded with zeros to the
+// byte length of the curve order, e.g. 64 bytes for P256. Most other
+// libraries (OpenSSL, Java, Go's ecdsa.SignASN1) use the ASN.1 DER encoding
+// instead. The functions in this file convert between the two.
+
+// ecdsaScalarLength returns the byte length of R or S for a curve.
+func ecdsaScalarLength(curve elliptic.Curve) int {
+	return (curve.Params().N.BitLen() + 7) / 8
+}
+
+type ecdsaASN1Signature struct {
+	R, S *big.Int
+}
+
+// EncodeEcdsaSignature returns the fixed width R||S encoding of a signature,
+// scalarLength is the byte length of each of R and S.
+func EncodeEcdsaSignature(R, S *big.Int, scalarLength int) ([]byte, error) {
+	if R.Sign() <= 0 || S.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: R and S must be positive", ErrSignatureEncoding)
+	}
+	if R.BitLen() > scalarLength*8 || S.BitLen() > scalarLength*8 {
+		return nil, fmt.Errorf(
+			"%w: R and S must fit in %d bytes", ErrSignatureEncoding, scalarLength)
+	}
+	signature := make([]byte, 2*scalarLength)
+	R.FillBytes(signature[:scalarLength])
+	S.FillBytes(signature[scalarLength:])
+	return signature, nil
+}
+
+// DecodeEcdsaSignature splits a fixed width R||S signature into its integers.
+// The signature must be exactly 2 * scalarLength bytes.
+func DecodeEcdsaSignature(signature []byte, scalarLength int) (*big.Int, *big.Int, error) {
+	if len(signature) != 2*scalarLength {
+		return nil, nil, fmt.Errorf(
+			"%w: expected %d bytes, got %d bytes",
+			ErrSignatureLength, 2*scalarLength, len(signature))
+	}
+	R := new(big.Int).SetBytes(signature[:scalarLength])
+	S := new(big.Int).SetBytes(signature[scalarLength:])
+	return R, S, nil
+}
+
+// EncodeEcdsaSignatureASN1 returns the ASN.1 DER encoding of a signature.
+func EncodeEcdsaSignatureASN1(R, S *big.Int) ([]byte, error) {
+	if R.Sign() <= 0 || S.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: R and S must be positive", ErrSignatureEncoding)
+	}
+	return asn1.Marshal(ecdsaASN1Signature{R: R, S: S})
+}
+
+// DecodeEcdsaSignatureASN1 parses an ASN.1 DER encoded signature. Trailing
+// data and non positive integers are rejected.
+func DecodeEcdsaSignatureASN1(der []byte) (*big.Int, *big.Int, error) {
+	var sig ecdsaASN1Signature
+	rest, err := asn1.Unmarshal(der, &sig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrSignatureEncoding, err)
+	}
+	if len(rest) != 0 {
+		return nil, nil, fmt.Errorf("%w: trailing data after signature", ErrSignatureEncoding)
+	}
+	if sig.R.Sign() <= 0 || sig.S.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("%w: R and S must be positive", ErrSignatureEncoding)
+	}
+	return sig.R, sig.S, nil
+}
+
+// EcdsaSignatureToASN1 converts a fixed width R||S signature, as returned by
+// the ecdsa signers in this package, to ASN.1 DER.
+func EcdsaSignatureToASN1(signature []byte) ([]byte, error) {
+	if len(signature) == 0 || len(signature)%2 != 0 {
+		return nil, fmt.Errorf(
+			"%w: expected an even number of bytes, got %d bytes",
+			ErrSignatureLength, len(signature))
+	}
+	R, S, err := DecodeEcdsaSignature(signature, len(signature)/2)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeEcdsaSignatureASN1(R, S)
+}
+
+// EcdsaSignatureFromASN1 converts an ASN.1 DER signature to the fixed width
+// R||S encoding used by this package, scalarLength is the byte length of each
+// of R and S, e.g. 32 for P256.
+func EcdsaSignatureFromASN1(der []byte, scalarLength int) ([]byte, error) {
+	R, S, err := DecodeEcdsaSignatureASN1(der)
+	if err != nil {
+		return nil, err
+	}
+	return EncodeEcdsaSignature(R, S, scalarLength)
+}
@@ -0,0 +1,131 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestEncodeEcdsaSignaturePads(t *testing.T) {
+	R := big.NewInt(1)
+	S := new(big.Int).SetBytes([]byte{0xff, 0xee})
+	signature, err := EncodeEcdsaSignature(R, S, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := []byte{0, 0, 0, 1, 0, 0, 0xff, 0xee}
+	if !reflect.DeepEqual(signature, expected) {
+		t.Errorf("Expected %x, got %x", expected, signature)
+	}
+
+	decodedR, decodedS, err := DecodeEcdsaSignature(signature, 4)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if decodedR.Cmp(R) != 0 || decodedS.Cmp(S) != 0 {
+		t.Errorf("Decoded values do not match")
+	}
+}
+
+func TestEncodeEcdsaSignatureTooLarge(t *testing.T) {
+	R := new(big.Int).SetBytes([]byte{1, 0, 0, 0, 0})
+	_, err := EncodeEcdsaSignature(R, big.NewInt(1), 4)
+	if !errors.Is(err, ErrSignatureEncoding) {
+		t.Errorf("Expected ErrSignatureEncoding, got %v", err)
+	}
+	_, err = EncodeEcdsaSignature(big.NewInt(0), big.NewInt(1), 4)
+	if !errors.Is(err, ErrSignatureEncoding) {
+		t.Errorf("Expected ErrSignatureEncoding, got %v", err)
+	}
+}
+
+func TestDecodeEcdsaSignatureBadLength(t *testing.T) {
+	_, _, err := DecodeEcdsaSignature(make([]byte, 63), 32)
+	if !errors.Is(err, ErrSignatureLength) {
+		t.Errorf("Expected ErrSignatureLength, got %v", err)
+	}
+}
+
+func TestEcdsaSignatureASN1Interop(t *testing.T) {
+	privKey, _ := FromHex(P256PrivHex)
+	pubKey, _ := FromHex(P256PubHex)
+	verifier, _ := NewP256Sha_256Verifier(pubKey)
+	stdKey := verifier.(*EcdsaVerifier).publicKey
+	priv := &ecdsa.PrivateKey{PublicKey: *stdKey, D: new(big.Int).SetBytes(privKey)}
+	message := []byte{1, 2, 3}
+	digest := (&Sha_256Hasher{}).Hash(message)
+
+	der, err := ecdsa.SignASN1(rand.Reader, priv, digest)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	signature, err := EcdsaSignatureFromASN1(der, 32)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(signature) != P256SignatureLength {
+		t.Fatalf("Expected %d bytes, got %d", P256SignatureLength, len(signature))
+	}
+	if !verifier.Verify(message, signature) {
+		t.Errorf("Expected converted signature to verify, it did not.")
+	}
+
+	backToDER, err := EcdsaSignatureToASN1(signature)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(der, backToDER) {
+		t.Errorf("Expected %x, got %x", der, backToDER)
+	}
+	if !ecdsa.VerifyASN1(stdKey, digest, backToDER) {
+		t.Errorf("Expected standard library to verify converted signature.")
+	}
+}
+
+var badASN1Signatures = []struct {
+	name string
+	der  string
+}{
+	{"empty", ""},
+	{"not_sequence", "020101"},
+	{"trailing_data", "3006020101020101" + "00"},
+	{"negative_r", "30060201ff020101"},
+	{"zero_s", "3006020101020100"},
+	{"non_minimal_integer", "300702020001020101"},
+	{"truncated", "30060201010201"},
+}
+
+func TestDecodeEcdsaSignatureASN1Invalid(t *testing.T) {
+	for _, tt := range badASN1Signatures {
+		t.Run(tt.name, func(t *testing.T) {
+			der, _ := FromHex(tt.der)
+			_, _, err := DecodeEcdsaSignatureASN1(der)
+			if !errors.Is(err, ErrSignatureEncoding) {
+				t.Errorf("Expected ErrSignatureEncoding, got %v", err)
+			}
+		})
+	}
+}
+
+func TestEcdsaSignatureToASN1BadLength(t *testing.T) {
+	_, err := EcdsaSignatureToASN1([]byte{1, 2, 3})
+	if !errors.Is(err, ErrSignatureLength) {
+		t.Errorf("Expected ErrSignatureLength, got %v", err)
+	}
+	_, err = EcdsaSignatureToASN1(nil)
+	if !errors.Is(err, ErrSignatureLength) {
+		t.Errorf("Expected ErrSignatureLength, got %v", err)
+	}
+}
+
+func TestEcdsaSignatureFromASN1TooLarge(t *testing.T) {
+	der, _ := EncodeEcdsaSignatureASN1(
+		new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(1))
+	_, err := EcdsaSignatureFromASN1(der, 32)
+	if !errors.Is(err, ErrSignatureEncoding) {
+		t.Errorf("Expected ErrSignatureEncoding, got %v", err)
+	}
+}
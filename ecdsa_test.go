@@ -3,11 +3,16 @@ package crypto
 import (
 	"crypto/ecdsa"
 	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
 	"fmt"
 	"io"
+	"math/big"
 	"reflect"
 	"strings"
 	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
 )
 
 const P256PubHex = "0e609d4eea6ecac33fd083bf108e90db5a31fbf9239bc5cc19a8a6dd10b61050c746f61b03ab399bcc5d18bd33953b4e73a4fdf7529f58747304a32c4814d24e"
@@ -30,6 +35,16 @@ func newAllBytesEight(hasher Hasher, messageHash []byte, privKey []byte) io.Read
 	}
 }
 
+// Fixed width encoding of a P256 signature, falls back to the unpadded
+// encoding for values too large to fit.
+func p256Signature(r, s *big.Int) []byte {
+	signature, err := EncodeEcdsaSignature(r, s, 32)
+	if err != nil {
+		return append(r.Bytes(), s.Bytes()...)
+	}
+	return signature
+}
+
 var signingTestCases = []struct {
 	message []byte
 }{
@@ -63,7 +78,7 @@ func TestEcdsaSignerSign(t *testing.T) {
 
 			result, err := signer.Sign(tt.message)
 			r, s, _ := ecdsa.Sign(allBytesEight, testKey, hasher.Hash(tt.message))
-			expected := append(r.Bytes(), s.Bytes()...)
+			expected := p256Signature(r, s)
 
 			if err != nil {
 				t.Errorf("Signer.Sign returned unexpected error: %s", err)
@@ -94,7 +109,7 @@ func TestEcdsaSignerVerifyPass(t *testing.T) {
 			}
 
 			r, s, _ := ecdsa.Sign(allBytesEight, testKey, hasher.Hash(tt.message))
-			signature := append(r.Bytes(), s.Bytes()...)
+			signature := p256Signature(r, s)
 
 			if !signer.Verify(tt.message, signature) {
 				t.Errorf("Expected signature to verify, it did not.")
@@ -122,7 +137,7 @@ func TestEcdsaSignerVerifyFailBadSignature(t *testing.T) {
 
 			r, s, _ := ecdsa.Sign(allBytesEight, testKey, hasher.Hash(tt.message))
 			r = r.Add(r, s)
-			signature := append(r.Bytes(), s.Bytes()...)
+			signature := p256Signature(r, s)
 
 			if signer.Verify(tt.message, signature) {
 				t.Errorf("Expected signature to not verify, it did.")
@@ -150,7 +165,7 @@ func TestEcdsaSignerVerifyFailMismatchHasher(t *testing.T) {
 
 			r, s, _ := ecdsa.Sign(allBytesEight, testKey, hasher.Hash(tt.message))
 			r = r.Add(r, s)
-			signature := append(r.Bytes(), s.Bytes()...)
+			signature := p256Signature(r, s)
 
 			if signer.Verify(tt.message, signature) {
 				t.Errorf("Expected signature to not verify, it did.")
@@ -179,7 +194,7 @@ func TestEcdsaSignerVerifyFailBadKey(t *testing.T) {
 
 			r, s, _ := ecdsa.Sign(allBytesEight, testKey, hasher.Hash(tt.message))
 			r = r.Add(r, s)
-			signature := append(r.Bytes(), s.Bytes()...)
+			signature := p256Signature(r, s)
 
 			if signer.Verify(tt.message, signature) {
 				t.Errorf("Expected signature to not verify, it did.")
@@ -208,7 +223,7 @@ func TestEcdsaSignerVerifyFailBadMessage(t *testing.T) {
 
 			r, s, _ := ecdsa.Sign(allBytesEight, testKey, hasher.Hash(badMessage))
 			r = r.Add(r, s)
-			signature := append(r.Bytes(), s.Bytes()...)
+			signature := p256Signature(r, s)
 
 			if signer.Verify(tt.message, signature) {
 				t.Errorf("Expected signature to not verify, it did.")
@@ -230,8 +245,16 @@ var constructorTestCases = []struct {
 	{NewP256Sha3_256DetSigner, NewP256Sha3_256Verifier, P256PubHex, P256PrivHex, []byte{1, 2}, true},
 	{NewP256Sha3_256InDetSigner, NewP256Sha3_256Verifier, P256PubHex, P256PrivHex, nil, false},
 	{NewP256Sha3_256InDetSigner, NewP256Sha3_256Verifier, P256PubHex, P256PrivHex, []byte{1, 2}, false},
+	{NewP256Sha3_256RFC6979Signer, NewP256Sha3_256Verifier, P256PubHex, P256PrivHex, nil, true},
+	{NewP256Sha3_256RFC6979Signer, NewP256Sha3_256Verifier, P256PubHex, P256PrivHex, []byte{1, 2}, true},
+	{NewP256Sha_256RFC6979Signer, NewP256Sha_256Verifier, P256PubHex, P256PrivHex, nil, true},
+	{NewP256Sha_256RFC6979Signer, NewP256Sha_256Verifier, P256PubHex, P256PrivHex, []byte{1, 2}, true},
+	{NewP256Sha_256InDetSigner, NewP256Sha_256Verifier, P256PubHex, P256PrivHex, nil, false},
+	{NewP256Sha_256InDetSigner, NewP256Sha_256Verifier, P256PubHex, P256PrivHex, []byte{1, 2}, false},
 	{NewP256Shake256DetSigner, NewP256Shake256Verifier, P256PubHex, P256PrivHex, nil, true},
 	{NewP256Shake256DetSigner, NewP256Shake256Verifier, P256PubHex, P256PrivHex, []byte{1, 2}, true},
+	{NewP256Shake256RFC6979Signer, NewP256Shake256Verifier, P256PubHex, P256PrivHex, nil, true},
+	{NewP256Shake256RFC6979Signer, NewP256Shake256Verifier, P256PubHex, P256PrivHex, []byte{1, 2}, true},
 	{NewP256Shake256InDetSigner, NewP256Shake256Verifier, P256PubHex, P256PrivHex, nil, false},
 	{NewP256Shake256InDetSigner, NewP256Shake256Verifier, P256PubHex, P256PrivHex, []byte{1, 2}, false},
 }
@@ -294,3 +317,320 @@ func TestEcdsaConstructorPairSuccess(t *testing.T) {
 		})
 	}
 }
+
+func TestEcdsaRFC6979DiffersFromDet(t *testing.T) {
+	privKey, _ := FromHex(P256PrivHex)
+	detSigner, _ := NewP256Sha3_256DetSigner(privKey)
+	rfcSigner, _ := NewP256Sha3_256RFC6979Signer(privKey)
+	message := []byte{1, 2}
+
+	detSignature, _ := detSigner.Sign(message)
+	rfcSignature, _ := rfcSigner.Sign(message)
+
+	if reflect.DeepEqual(detSignature, rfcSignature) {
+		t.Errorf("Expected RFC 6979 signature to differ from the legacy deterministic one.")
+	}
+	if !detSigner.Verify(message, rfcSignature) {
+		t.Errorf("Expected RFC 6979 signature to verify with the same suite verifier.")
+	}
+}
+
+func TestEcdsaVerifierRejectsBadLength(t *testing.T) {
+	privKey, _ := FromHex(P256PrivHex)
+	pubKey, _ := FromHex(P256PubHex)
+	signer, _ := NewP256Sha3_256DetSigner(privKey)
+	verifier, _ := NewP256Sha3_256Verifier(pubKey)
+	message := []byte{1, 2}
+	signature, _ := signer.Sign(message)
+
+	if len(signature) != P256SignatureLength {
+		t.Fatalf("Expected %d byte signature, got %d", P256SignatureLength, len(signature))
+	}
+	if verifier.Verify(message, signature[1:]) {
+		t.Errorf("Expected short signature to not verify, it did.")
+	}
+	if verifier.Verify(message, append([]byte{0}, signature...)) {
+		t.Errorf("Expected long signature to not verify, it did.")
+	}
+}
+
+func TestP256SignersRejectInvalidKeys(t *testing.T) {
+	privKey, _ := FromHex(P256PrivHex)
+	order := elliptic.P256().Params().N.Bytes()
+	constructors := []func([]byte) (Signer, error){
+		NewP256Sha3_256DetSigner,
+		NewP256Sha3_256RFC6979Signer,
+		NewP256Sha3_256InDetSigner,
+		NewP256Shake256DetSigner,
+		NewP256Shake256RFC6979Signer,
+		NewP256Shake256InDetSigner,
+	}
+	for i, constructor := range constructors {
+		for _, bad := range [][]byte{privKey[1:], make([]byte, 32), order} {
+			if _, err := constructor(bad); err == nil {
+				t.Errorf("%d: Expected an error for private key %x", i, bad)
+			}
+		}
+	}
+}
+
+func TestEcdsaSignerPadsSignature(t *testing.T) {
+	privKey, _ := FromHex(P256PrivHex)
+	signer, _ := NewP256Sha3_256InDetSigner(privKey)
+	// With random k roughly 1 in 128 signatures has a leading zero byte in R
+	// or S, they must still be P256SignatureLength bytes and verify.
+	for i := 0; i < 512; i++ {
+		message := []byte{byte(i), byte(i >> 8)}
+		signature, err := signer.Sign(message)
+		if err != nil {
+			t.Fatalf("Signer.Sign returned unexpected error: %s", err)
+		}
+		if len(signature) != P256SignatureLength {
+			t.Fatalf("Expected %d byte signature, got %d", P256SignatureLength, len(signature))
+		}
+		if !signer.Verify(message, signature) {
+			t.Fatalf("Signer didn't verify signature %x", signature)
+		}
+	}
+}
+
+// Returns signature with S replaced by N - S if S is high, as the signers
+// normalize it.
+func lowSSignature(curve elliptic.Curve, signature []byte) []byte {
+	R, S := splitByteSlice(signature)
+	N := curve.Params().N
+	if S.Cmp(new(big.Int).Rsh(N, 1)) > 0 {
+		S.Sub(N, S)
+	}
+	normalized, _ := EncodeEcdsaSignature(R, S, len(signature)/2)
+	return normalized
+}
+
+// RFC 6979 appendix A.2.5, P256 with SHA-256, with S normalized.
+func TestP256Sha_256RFC6979Vectors(t *testing.T) {
+	privKey, _ := FromHex(rfc6979P256PrivHex)
+	signer, err := NewP256Sha_256RFC6979Signer(privKey)
+	if err != nil {
+		t.Fatalf("Error creating the signer: %s", err)
+	}
+	for _, tt := range rfc6979TestCases[:2] {
+		t.Run(tt.name, func(t *testing.T) {
+			signature, err := signer.Sign([]byte(tt.message))
+			if err != nil {
+				t.Fatalf("Signer.Sign returned unexpected error: %s", err)
+			}
+			rfcSignature, _ := FromHex(tt.r + tt.s)
+			expected := ToHex(lowSSignature(elliptic.P256(), rfcSignature))
+			if ToHex(signature) != expected {
+				t.Errorf("Expected %s, signature was %x.", expected, signature)
+			}
+		})
+	}
+}
+
+func TestP256Sha_256StdlibInterop(t *testing.T) {
+	privKey, _ := FromHex(P256PrivHex)
+	pubKey, _ := FromHex(P256PubHex)
+	signer, _ := NewP256Sha_256InDetSigner(privKey)
+	verifier, _ := NewP256Sha_256Verifier(pubKey)
+	stdKey := verifier.(*EcdsaVerifier).publicKey
+	message := []byte{1, 2, 3}
+	digest := (&Sha_256Hasher{}).Hash(message)
+
+	signature, _ := signer.Sign(message)
+	der, _ := EcdsaSignatureToASN1(signature)
+	if !ecdsa.VerifyASN1(stdKey, digest, der) {
+		t.Errorf("Expected standard library to verify signature.")
+	}
+
+	stdPriv := &ecdsa.PrivateKey{PublicKey: *stdKey, D: new(big.Int).SetBytes(privKey)}
+	stdDER, _ := ecdsa.SignASN1(rand.Reader, stdPriv, digest)
+	stdSignature, _ := EcdsaSignatureFromASN1(stdDER, 32)
+	if !verifier.Verify(message, stdSignature) {
+		t.Errorf("Expected to verify standard library signature.")
+	}
+}
+
+// Returns the (R, N - S) twin of a valid signature.
+func ecdsaTwinSignature(curve elliptic.Curve, signature []byte) []byte {
+	R, S := splitByteSlice(signature)
+	twin, _ := EncodeEcdsaSignature(R, S.Sub(curve.Params().N, S), len(signature)/2)
+	return twin
+}
+
+func TestEcdsaSignersCreateLowS(t *testing.T) {
+	testCases := []struct {
+		suiteType  string
+		curve      elliptic.Curve
+		privKeyHex string
+	}{
+		{"ecdsa_P256_sha-256_indet", elliptic.P256(), P256PrivHex},
+		{"ecdsa_P256_sha3-256_det", elliptic.P256(), P256PrivHex},
+		{"ecdsa_P384_sha-384_rfc6979", elliptic.P384(), P384PrivHex},
+		{"ecdsa_P521_sha-512_indet", elliptic.P521(), P521PrivHex},
+		{"ecdsa_secp256k1_sha-256", secp256k1.S256(), Secp256k1PrivHex},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.suiteType, func(t *testing.T) {
+			privKey, _ := FromHex(tt.privKeyHex)
+			signer, err := NewSignerForSuite(tt.suiteType, privKey)
+			if err != nil {
+				t.Fatalf("Error creating the signer: %s", err)
+			}
+			halfOrder := new(big.Int).Rsh(tt.curve.Params().N, 1)
+			for i := 0; i < 32; i++ {
+				signature, err := signer.Sign([]byte{byte(i)})
+				if err != nil {
+					t.Fatalf("Error signing: %s", err)
+				}
+				if _, S := splitByteSlice(signature); S.Cmp(halfOrder) > 0 {
+					t.Fatalf("Expected a low S, got %x.", S)
+				}
+			}
+		})
+	}
+}
+
+func TestNewLowSVerifier(t *testing.T) {
+	testCases := []struct {
+		suiteType  string
+		curve      elliptic.Curve
+		privKeyHex string
+		pubKeyHex  string
+	}{
+		{"ecdsa_P256_sha-256_rfc6979", elliptic.P256(), P256PrivHex, P256PubHex},
+		{"ecdsa_P384_sha-384_indet", elliptic.P384(), P384PrivHex, P384PubHex},
+		{"ecdsa_secp256k1_sha-256", secp256k1.S256(), Secp256k1PrivHex, Secp256k1PubHex},
+	}
+	message := []byte{1, 2, 3}
+	for _, tt := range testCases {
+		t.Run(tt.suiteType, func(t *testing.T) {
+			privKey, _ := FromHex(tt.privKeyHex)
+			pubKey, _ := FromHex(tt.pubKeyHex)
+			signer, _ := NewSignerForSuite(tt.suiteType, privKey)
+			verifier, _ := NewVerifierForSuite(tt.suiteType, pubKey)
+			signature, _ := signer.Sign(message)
+			twin := ecdsaTwinSignature(tt.curve, signature)
+			if !verifier.Verify(message, twin) {
+				t.Errorf("Expected the default verifier to accept a high S.")
+			}
+
+			for _, v := range []Verifier{verifier, signer} {
+				strict, err := NewLowSVerifier(v)
+				if err != nil {
+					t.Fatalf("Unexpected error: %s", err)
+				}
+				if strict.SuiteType() != v.SuiteType() {
+					t.Errorf("Got suite %s, expected %s", strict.SuiteType(), v.SuiteType())
+				}
+				if !strict.Verify(message, signature) {
+					t.Errorf("Expected to verify signature.")
+				}
+				err = strict.(ErrorVerifier).VerifyErr(message, twin)
+				if !errors.Is(err, ErrSignatureEncoding) {
+					t.Errorf("Got %v, expected %v", err, ErrSignatureEncoding)
+				}
+			}
+			// The original is left as it was.
+			if !verifier.Verify(message, twin) {
+				t.Errorf("Expected the default verifier to accept a high S.")
+			}
+		})
+	}
+}
+
+func TestNewLowSVerifierSuites(t *testing.T) {
+	k1PubKey, _ := FromHex(Secp256k1PubHex)
+	keccak, _ := NewSecp256k1Keccak256Verifier(k1PubKey)
+	if strict, err := NewLowSVerifier(keccak); err != nil || strict != keccak {
+		t.Errorf("Expected the keccak256 verifier to be returned as is, got %v.", err)
+	}
+	edPubKey, _ := FromHex(Ed25519PubHex)
+	ed, _ := NewEd25519Verifier(edPubKey)
+	if _, err := NewLowSVerifier(ed); !errors.Is(err, ErrUnexpectedSuite) {
+		t.Errorf("Got %v, expected %v", err, ErrUnexpectedSuite)
+	}
+}
+
+func TestSetRequireLowS(t *testing.T) {
+	testCases := []struct {
+		suiteType  string
+		curve      elliptic.Curve
+		privKeyHex string
+		pubKeyHex  string
+	}{
+		{"ecdsa_P256_sha-256_rfc6979", elliptic.P256(), P256PrivHex, P256PubHex},
+		{"ecdsa_P256_sha3-256_det", elliptic.P256(), P256PrivHex, P256PubHex},
+		{"ecdsa_secp256k1_sha-256", secp256k1.S256(), Secp256k1PrivHex, Secp256k1PubHex},
+	}
+	message := []byte("message")
+	for _, tt := range testCases {
+		t.Run(tt.suiteType, func(t *testing.T) {
+			privKey, _ := FromHex(tt.privKeyHex)
+			resolve := newEnvelopeResolver(map[string]string{"key-1": tt.pubKeyHex})
+			if err := SetRequireLowS(tt.suiteType, true); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			defer SetRequireLowS(tt.suiteType, false)
+
+			signer, err := NewSignerForSuite(tt.suiteType, privKey)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			envelope, _ := SignEnvelope(signer, "key-1", message, EnvelopeAttributes{})
+			twinEnvelope := *envelope
+			twinEnvelope.Signature = ecdsaTwinSignature(tt.curve, envelope.Signature)
+			if ok, err := VerifyEnvelope(envelope, message, resolve); !ok || err != nil {
+				t.Errorf("Expected to verify envelope, got %v.", err)
+			}
+			if ok, _ := VerifyEnvelope(&twinEnvelope, message, resolve); ok {
+				t.Errorf("Expected envelope with a high S to not verify.")
+			}
+
+			signature, _ := signer.Sign(message)
+			twin := ecdsaTwinSignature(tt.curve, signature)
+			if signer.Verify(message, twin) {
+				t.Errorf("Expected signer to reject a high S.")
+			}
+			pubKey, _ := FromHex(tt.pubKeyHex)
+			verifier, err := NewVerifierForSuite(tt.suiteType, pubKey)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if !verifier.Verify(message, signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+			if err := VerifyErr(verifier, message, twin); !errors.Is(err, ErrSignatureEncoding) {
+				t.Errorf("Got %v, expected %v", err, ErrSignatureEncoding)
+			}
+
+			if err := SetRequireLowS(tt.suiteType, false); err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			verifier, _ = NewVerifierForSuite(tt.suiteType, pubKey)
+			if !verifier.Verify(message, twin) {
+				t.Errorf("Expected the default verifier to accept a high S.")
+			}
+		})
+	}
+}
+
+func TestSetRequireLowSInvalid(t *testing.T) {
+	if err := SetRequireLowS("ed25519", true); !errors.Is(err, ErrUnexpectedSuite) {
+		t.Errorf("Got %v, expected %v", err, ErrUnexpectedSuite)
+	}
+	if err := SetRequireLowS("ecdsa_P256_unknown", true); !errors.Is(err, ErrUnknownSuite) {
+		t.Errorf("Got %v, expected %v", err, ErrUnknownSuite)
+	}
+	// Recoverable signatures already have a low S.
+	if err := SetRequireLowS("ecdsa_secp256k1_keccak256", true); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	defer SetRequireLowS("ecdsa_secp256k1_keccak256", false)
+	privKey, _ := FromHex(Secp256k1PrivHex)
+	signer, _ := NewSignerForSuite("ecdsa_secp256k1_keccak256", privKey)
+	signature, _ := signer.Sign([]byte("message"))
+	if !signer.Verify([]byte("message"), signature) {
+		t.Errorf("Expected to verify signature.")
+	}
+}
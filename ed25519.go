@@ -1,10 +1,15 @@
 package crypto
 
 import (
+	"crypto"
+	"crypto/sha512"
 	"errors"
 	"fmt"
+	"hash"
 
 	"crypto/ed25519"
+
+	"filippo.io/edwards25519"
 )
 
 // There are two curves commonly used in the 25519 family, c25519 and ed25519,
@@ -20,31 +25,92 @@ const (
 	X25519SignatureLength = 64
 )
 
+func init() {
+	mustRegisterSuite("ed25519", NewEd25519Signer, NewEd25519Verifier)
+	mustRegisterSuite("ed25519ph", NewEd25519phSigner, NewEd25519phVerifier)
+}
+
 // NewEd25519Verifier constructor for ed25519 Verifier
 func NewEd25519Verifier(pubKey []byte) (Verifier, error) {
+	return newEd25519Verifier(pubKey, nil, "ed25519")
+}
+
+// NewEd25519Signer constructor for ed25519 Signer
+func NewEd25519Signer(privKey []byte) (Signer, error) {
+	return newEd25519Signer(privKey, nil, "ed25519")
+}
+
+// NewEd25519phVerifier constructor for an Ed25519ph Verifier. Ed25519ph signs
+// the SHA-512 hash of the message instead of the message itself, see RFC 8032.
+func NewEd25519phVerifier(pubKey []byte) (Verifier, error) {
+	return newEd25519Verifier(pubKey, &ed25519.Options{Hash: crypto.SHA512}, "ed25519ph")
+}
+
+// NewEd25519phSigner constructor for an Ed25519ph Signer. Ed25519ph signs the
+// SHA-512 hash of the message instead of the message itself, see RFC 8032.
+func NewEd25519phSigner(privKey []byte) (Signer, error) {
+	return newEd25519Signer(privKey, &ed25519.Options{Hash: crypto.SHA512}, "ed25519ph")
+}
+
+// NewEd25519ctxVerifier constructor for an Ed25519ctx Verifier. Signatures only
+// verify under the context they were created with, which must be 1 to 255
+// bytes long. As the context is required this suite is not registered for
+// NewVerifierForSuite.
+func NewEd25519ctxVerifier(pubKey []byte, context []byte) (Verifier, error) {
+	if err := checkEd25519Context(context); err != nil {
+		return nil, err
+	}
+	return newEd25519Verifier(pubKey, &ed25519.Options{Context: string(context)}, "ed25519ctx")
+}
+
+// NewEd25519ctxSigner constructor for an Ed25519ctx Signer. The context, 1 to
+// 255 bytes, separates signatures made for different purposes with the same
+// key. As the context is required this suite is not registered for
+// NewSignerForSuite.
+func NewEd25519ctxSigner(privKey []byte, context []byte) (Signer, error) {
+	if err := checkEd25519Context(context); err != nil {
+		return nil, err
+	}
+	return newEd25519Signer(privKey, &ed25519.Options{Context: string(context)}, "ed25519ctx")
+}
+
+// Ed25519ctx does not allow an empty context, that would be pure Ed25519.
+func checkEd25519Context(context []byte) error {
+	if len(context) == 0 || len(context) > 255 {
+		return errors.New("context should be 1 to 255 bytes got " + fmt.Sprint(len(context)))
+	}
+	return nil
+}
+
+// Convenience function for creating verifiers. Used in functions such as
+// NewEd25519phVerifier, nil options is pure Ed25519.
+func newEd25519Verifier(pubKey []byte, options *ed25519.Options, suiteType string) (Verifier, error) {
 	if len(pubKey) != X25519PublicKeyLength {
-		return nil, errors.New("key should be 32 bytes got " + fmt.Sprint(len(pubKey)))
+		return nil, fmt.Errorf("%w: key should be 32 bytes got %d", ErrInvalidPublicKey, len(pubKey))
 	}
 	return &ed25519Verifier{
 		publicKey: pubKey,
-		suiteType: "ed25519",
+		options:   options,
+		suiteType: suiteType,
 	}, nil
 }
 
-// NewEd25519Signer constructor for ed25519 Signer
-func NewEd25519Signer(privKey []byte) (Signer, error) {
+// Convenience function for creating signers. Used in functions such as
+// NewEd25519phSigner, nil options is pure Ed25519.
+func newEd25519Signer(privKey []byte, options *ed25519.Options, suiteType string) (Signer, error) {
 	if len(privKey) != X25519PrivateKeyLength {
 		return nil, errors.New("key should be 64 bytes got " + fmt.Sprint(len(privKey)))
 	}
 
-	verifier, verErr := NewEd25519Verifier(privKey[32:])
+	verifier, verErr := newEd25519Verifier(privKey[32:], options, suiteType)
 	if verErr != nil {
 		return nil, verErr
 	}
 
 	return &ed25519Signer{
 		privKey:  privKey,
-		verifier: verifier,
+		options:  options,
+		verifier: verifier.(*ed25519Verifier),
 	}, nil
 }
 
@@ -74,6 +140,7 @@ func Ed25519KeyPairFromSeed(seed []byte) (pub []byte, priv []byte, err error) {
 // ed25519Verifier Verifies a signature using Curve25519 and ed25519
 type ed25519Verifier struct {
 	publicKey ed25519.PublicKey
+	options   *ed25519.Options
 	suiteType string
 }
 
@@ -81,26 +148,143 @@ func (s *ed25519Verifier) SuiteType() string {
 	return s.suiteType
 }
 
+// PublicKey returns the public key, see PublicKeyHolder.
+func (s *ed25519Verifier) PublicKey() []byte {
+	return append([]byte{}, s.publicKey...)
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *ed25519Verifier) KeyID() string {
+	return KeyIDFromPublicKey(s.PublicKey())
+}
+
 func (s *ed25519Verifier) Verify(toVerify []byte, signature []byte) bool {
-	if len(signature) != 64 {
-		return false
+	return s.VerifyErr(toVerify, signature) == nil
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *ed25519Verifier) VerifyErr(toVerify []byte, signature []byte) error {
+	if s.options == nil {
+		return s.verifyMessage(toVerify, signature)
+	}
+	return s.verifyMessage(ed25519PrehashMessage(toVerify, s.options), signature)
+}
+
+// Verifies a signature over the message or, for Ed25519ph, its digest.
+func (s *ed25519Verifier) verifyMessage(message []byte, signature []byte) error {
+	if len(signature) != ed25519.SignatureSize {
+		return fmt.Errorf(
+			"%w: expected %d bytes, got %d bytes",
+			ErrSignatureLength, ed25519.SignatureSize, len(signature))
+	}
+	// Keys are only checked for their length when the verifier is created.
+	if _, err := new(edwards25519.Point).SetBytes(s.publicKey); err != nil {
+		return fmt.Errorf("%w: not a point on the curve", ErrInvalidPublicKey)
 	}
-	return ed25519.Verify(s.publicKey, toVerify, signature)
+	if _, err := edwards25519.NewScalar().SetCanonicalBytes(signature[32:]); err != nil {
+		return fmt.Errorf("%w: S is not less than the group order", ErrSignatureEncoding)
+	}
+	options := s.options
+	if options == nil {
+		options = &ed25519.Options{}
+	}
+	if ed25519.VerifyWithOptions(s.publicKey, message, signature, options) != nil {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+// Only Ed25519ph signs a hash of the message.
+func (s *ed25519Verifier) prehashed() bool {
+	return s.options != nil && s.options.Hash == crypto.SHA512
+}
+
+func (s *ed25519Verifier) newHash() hash.Hash {
+	if !s.prehashed() {
+		return nil
+	}
+	return sha512.New()
+}
+
+func (s *ed25519Verifier) verifyDigest(digest []byte, signature []byte) error {
+	if !s.prehashed() {
+		return fmt.Errorf("%w: %s", ErrSuiteCannotPrehash, s.SuiteType())
+	}
+	return s.verifyMessage(digest, signature)
+}
+
+// VerifyDigest verifies a signature over a digest, see DigestVerifier.
+func (s *ed25519Verifier) VerifyDigest(digest []byte, signature []byte) bool {
+	return verifyCheckedDigest(s, digest, signature)
+}
+
+// Ed25519ph signs the SHA-512 hash of the message, other variants sign the
+// message as is.
+func ed25519PrehashMessage(message []byte, options *ed25519.Options) []byte {
+	if options.Hash != crypto.SHA512 {
+		return message
+	}
+	digest := sha512.Sum512(message)
+	return digest[:]
 }
 
 type ed25519Signer struct {
 	privKey  ed25519.PrivateKey
-	verifier Verifier
+	options  *ed25519.Options
+	verifier *ed25519Verifier
 }
 
 func (s *ed25519Signer) Sign(toSign []byte) ([]byte, error) {
-	return ed25519.Sign(s.privKey, toSign), nil
+	if s.options == nil {
+		return ed25519.Sign(s.privKey, toSign), nil
+	}
+	return s.privKey.Sign(nil, ed25519PrehashMessage(toSign, s.options), s.options)
+}
+
+func (s *ed25519Signer) signDigest(digest []byte) ([]byte, error) {
+	if !s.verifier.prehashed() {
+		return nil, fmt.Errorf("%w: %s", ErrSuiteCannotPrehash, s.SuiteType())
+	}
+	return s.privKey.Sign(nil, digest, s.options)
 }
 
 func (s *ed25519Signer) Verify(toVerify []byte, signature []byte) bool {
 	return s.verifier.Verify(toVerify, signature)
 }
 
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *ed25519Signer) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifier.VerifyErr(toVerify, signature)
+}
+
+func (s *ed25519Signer) verifyDigest(digest []byte, signature []byte) error {
+	return s.verifier.verifyDigest(digest, signature)
+}
+
+func (s *ed25519Signer) newHash() hash.Hash {
+	return s.verifier.newHash()
+}
+
+// SignDigest signs a digest computed with the suite's hash, see DigestSigner.
+func (s *ed25519Signer) SignDigest(digest []byte) ([]byte, error) {
+	return signCheckedDigest(s, digest)
+}
+
+// VerifyDigest verifies a signature over a digest, see DigestVerifier.
+func (s *ed25519Signer) VerifyDigest(digest []byte, signature []byte) bool {
+	return verifyCheckedDigest(s, digest, signature)
+}
+
 func (s *ed25519Signer) SuiteType() string {
 	return s.verifier.SuiteType()
 }
+
+// PublicKey returns the public key, see PublicKeyHolder.
+func (s *ed25519Signer) PublicKey() []byte {
+	return s.verifier.PublicKey()
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *ed25519Signer) KeyID() string {
+	return s.verifier.KeyID()
+}
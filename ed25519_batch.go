@@ -0,0 +1,172 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+
+	"filippo.io/edwards25519"
+)
+
+// BatchVerifier collects signatures and verifies them together, which is
+// cheaper than verifying each one on its own.
+type BatchVerifier interface {
+	// Add queues a signature over message by publicKey.
+	Add(publicKey []byte, message []byte, signature []byte)
+	// Len returns the number of queued signatures.
+	Len() int
+	// Verify returns true if every queued signature is valid. Otherwise it
+	// returns false and the indexes, in the order they were added, of the
+	// signatures that are not.
+	Verify() (bool, []int)
+}
+
+// NewEd25519BatchVerifier constructor for an ed25519 BatchVerifier. The batch
+// is checked with a single multiscalar multiplication where every signature is
+// weighted by a random 128 bit scalar, so an invalid signature can not be
+// cancelled out by another. If the batch fails each signature is verified on
+// its own to find the invalid ones.
+//
+// The batch equation and the check of single signatures used to find the
+// invalid ones are both multiplied by the cofactor, as RFC 8032 permits, so a
+// signature is valid or not whatever it is batched with. ed25519.Verify is
+// not cofactored, a signature crafted with small order components may pass
+// here and fail ed25519.Verify, honestly generated signatures verify the same
+// either way.
+func NewEd25519BatchVerifier() BatchVerifier {
+	return &ed25519BatchVerifier{}
+}
+
+type ed25519BatchEntry struct {
+	publicKey []byte
+	message   []byte
+	signature []byte
+}
+
+type ed25519BatchVerifier struct {
+	entries []ed25519BatchEntry
+}
+
+func (b *ed25519BatchVerifier) Add(publicKey []byte, message []byte, signature []byte) {
+	b.entries = append(b.entries, ed25519BatchEntry{
+		publicKey: publicKey,
+		message:   message,
+		signature: signature,
+	})
+}
+
+func (b *ed25519BatchVerifier) Len() int {
+	return len(b.entries)
+}
+
+func (b *ed25519BatchVerifier) Verify() (bool, []int) {
+	if len(b.entries) == 0 {
+		return true, nil
+	}
+
+	// Entries that can not be decoded are failures on their own and are left
+	// out of the batch equation.
+	var failed []int
+	n := len(b.entries)
+	scalars := make([]*edwards25519.Scalar, 1, 2*n+1)
+	points := make([]*edwards25519.Point, 1, 2*n+1)
+	sumS := edwards25519.NewScalar()
+	random := make([]byte, 32)
+	for i, entry := range b.entries {
+		A, R, s, k, ok := decodeEd25519BatchEntry(entry)
+		if !ok {
+			failed = append(failed, i)
+			continue
+		}
+		if _, err := rand.Read(random[:16]); err != nil {
+			failed = b.verifyEach()
+			return len(failed) == 0, failed
+		}
+		z, _ := edwards25519.NewScalar().SetCanonicalBytes(random)
+
+		// [8]( -(sum z_i s_i) B + sum z_i R_i + sum (z_i k_i) A_i ) = 0
+		sumS.MultiplyAdd(z, s, sumS)
+		scalars = append(scalars, z, edwards25519.NewScalar().Multiply(z, k))
+		points = append(points, R, A)
+	}
+	if len(failed) > 0 {
+		return false, b.verifyEach(failed...)
+	}
+
+	scalars[0] = edwards25519.NewScalar().Negate(sumS)
+	points[0] = edwards25519.NewGeneratorPoint()
+	check := new(edwards25519.Point).VarTimeMultiScalarMult(scalars, points)
+	check.MultByCofactor(check)
+	if check.Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return true, nil
+	}
+	failed = b.verifyEach()
+	return len(failed) == 0, failed
+}
+
+// Verifies every entry not in known on its own, returning the indexes of all
+// failed entries including known.
+func (b *ed25519BatchVerifier) verifyEach(known ...int) []int {
+	failed := make([]int, 0, len(known))
+	for i, entry := range b.entries {
+		if len(known) > 0 && known[0] == i {
+			failed = append(failed, i)
+			known = known[1:]
+			continue
+		}
+		A, R, s, k, ok := decodeEd25519BatchEntry(entry)
+		if !ok || !verifyEd25519Cofactored(A, R, s, k) {
+			failed = append(failed, i)
+		}
+	}
+	return failed
+}
+
+// Checks [8]([s]B - [k]A - R) = 0, the batch equation for a single entry.
+func verifyEd25519Cofactored(
+	A *edwards25519.Point,
+	R *edwards25519.Point,
+	s *edwards25519.Scalar,
+	k *edwards25519.Scalar,
+) bool {
+	minusK := edwards25519.NewScalar().Negate(k)
+	check := new(edwards25519.Point).VarTimeDoubleScalarBaseMult(minusK, A, s)
+	check.Subtract(check, R)
+	check.MultByCofactor(check)
+	return check.Equal(edwards25519.NewIdentityPoint()) == 1
+}
+
+// Decodes the public key A, the commitment R, the scalar s and the challenge
+// k = SHA-512(R || A || message) of an entry.
+func decodeEd25519BatchEntry(entry ed25519BatchEntry) (
+	A *edwards25519.Point,
+	R *edwards25519.Point,
+	s *edwards25519.Scalar,
+	k *edwards25519.Scalar,
+	ok bool,
+) {
+	if len(entry.publicKey) != ed25519.PublicKeySize || len(entry.signature) != ed25519.SignatureSize {
+		return nil, nil, nil, nil, false
+	}
+	A, err := new(edwards25519.Point).SetBytes(entry.publicKey)
+	if err != nil {
+		return nil, nil, nil, nil, false
+	}
+	// ed25519.Verify compares the encoding of R, so only the canonical
+	// encoding is accepted here as well.
+	R, err = new(edwards25519.Point).SetBytes(entry.signature[:32])
+	if err != nil || !bytes.Equal(R.Bytes(), entry.signature[:32]) {
+		return nil, nil, nil, nil, false
+	}
+	s, err = edwards25519.NewScalar().SetCanonicalBytes(entry.signature[32:])
+	if err != nil {
+		return nil, nil, nil, nil, false
+	}
+	h := sha512.New()
+	h.Write(entry.signature[:32])
+	h.Write(entry.publicKey)
+	h.Write(entry.message)
+	k, _ = edwards25519.NewScalar().SetUniformBytes(h.Sum(nil))
+	return A, R, s, k, true
+}
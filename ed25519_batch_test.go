@@ -0,0 +1,206 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"filippo.io/edwards25519"
+)
+
+type ed25519BatchTestEntry struct {
+	publicKey []byte
+	message   []byte
+	signature []byte
+}
+
+func ed25519BatchEntries(t testing.TB, n int) []ed25519BatchTestEntry {
+	entries := make([]ed25519BatchTestEntry, n)
+	for i := range entries {
+		pub, priv, err := GenerateEd25519KeyPair()
+		if err != nil {
+			t.Fatalf("Got a key generation error %s", err)
+		}
+		message := []byte(fmt.Sprintf("transaction %d", i))
+		entries[i] = ed25519BatchTestEntry{pub, message, ed25519.Sign(priv, message)}
+	}
+	return entries
+}
+
+func newEd25519Batch(entries []ed25519BatchTestEntry) BatchVerifier {
+	batch := NewEd25519BatchVerifier()
+	for _, entry := range entries {
+		batch.Add(entry.publicKey, entry.message, entry.signature)
+	}
+	return batch
+}
+
+func TestEd25519BatchVerifierValid(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 64} {
+		t.Run(fmt.Sprint(n), func(t *testing.T) {
+			batch := newEd25519Batch(ed25519BatchEntries(t, n))
+			if batch.Len() != n {
+				t.Errorf("Got %d, expected %d", batch.Len(), n)
+			}
+			ok, failed := batch.Verify()
+			if !ok || len(failed) != 0 {
+				t.Errorf("Expected batch to verify, failed entries %v", failed)
+			}
+		})
+	}
+}
+
+func TestEd25519BatchVerifierVector(t *testing.T) {
+	pubKey, _ := FromHex(Ed25519PubHex)
+	message, _ := FromHex(EdMessageHex)
+	signature, _ := FromHex(EdSignatureHex)
+	batch := NewEd25519BatchVerifier()
+	batch.Add(pubKey, message, signature)
+	batch.Add(pubKey, message, signature)
+	if ok, failed := batch.Verify(); !ok {
+		t.Errorf("Expected batch to verify, failed entries %v", failed)
+	}
+}
+
+func TestEd25519BatchVerifierReportsFailures(t *testing.T) {
+	entries := ed25519BatchEntries(t, 8)
+
+	// A signature from another key, a changed message, a signature with S
+	// out of range and malformed keys and signatures.
+	entries[1].signature = entries[2].signature
+	entries[3].message = []byte("changed")
+	highS := append([]byte{}, entries[4].signature...)
+	highS[63] |= 0xf0
+	entries[4].signature = highS
+	entries[5].publicKey = entries[5].publicKey[1:]
+	entries[7].signature = nil
+
+	ok, failed := newEd25519Batch(entries).Verify()
+	if ok {
+		t.Errorf("Expected batch to not verify.")
+	}
+	expected := []int{1, 3, 4, 5, 7}
+	if !reflect.DeepEqual(failed, expected) {
+		t.Errorf("Got %v, expected %v", failed, expected)
+	}
+}
+
+func TestEd25519BatchVerifierCancellingSignatures(t *testing.T) {
+	// Without random weights two signatures whose S values are off by
+	// opposite amounts would cancel out in the sum.
+	entries := ed25519BatchEntries(t, 2)
+	s0 := append([]byte{}, entries[0].signature...)
+	s1 := append([]byte{}, entries[1].signature...)
+	s0[32]++
+	s1[32]--
+	entries[0].signature, entries[1].signature = s0, s1
+
+	ok, failed := newEd25519Batch(entries).Verify()
+	if ok || !reflect.DeepEqual(failed, []int{0, 1}) {
+		t.Errorf("Expected both entries to fail, got %v", failed)
+	}
+}
+
+func TestEd25519BatchVerifierNonCanonicalR(t *testing.T) {
+	entries := ed25519BatchEntries(t, 1)
+	// The identity point encoded as y = p + 1 instead of y = 1, ed25519.Verify
+	// rejects it so the batch must too.
+	nonCanonical, _ := FromHex("eeffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	signature := append(nonCanonical, entries[0].signature[32:]...)
+	entries[0].signature = signature
+
+	ok, failed := newEd25519Batch(entries).Verify()
+	if ok || !reflect.DeepEqual(failed, []int{0}) {
+		t.Errorf("Expected entry to fail, got %v", failed)
+	}
+}
+
+// Signs message with R shifted by the point of order 2, so that
+// [s]B - [k]A - R is that point. It is valid under the cofactored equation
+// and rejected by ed25519.Verify.
+func ed25519TorsionSignature(t *testing.T, priv ed25519.PrivateKey, message []byte) []byte {
+	t.Helper()
+	h := sha512.Sum512(priv.Seed())
+	a, _ := edwards25519.NewScalar().SetBytesWithClamping(h[:32])
+	random := make([]byte, 64)
+	if _, err := rand.Read(random); err != nil {
+		t.Fatal(err)
+	}
+	r, _ := edwards25519.NewScalar().SetUniformBytes(random)
+	orderTwo, _ := FromHex("ecffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff7f")
+	T, err := new(edwards25519.Point).SetBytes(orderTwo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	R := new(edwards25519.Point).ScalarBaseMult(r)
+	R.Add(R, T)
+
+	digest := sha512.New()
+	digest.Write(R.Bytes())
+	digest.Write(priv.Public().(ed25519.PublicKey))
+	digest.Write(message)
+	k, _ := edwards25519.NewScalar().SetUniformBytes(digest.Sum(nil))
+	S := edwards25519.NewScalar().MultiplyAdd(k, a, r)
+	return append(R.Bytes(), S.Bytes()...)
+}
+
+func TestEd25519BatchVerifierSmallOrderComponent(t *testing.T) {
+	pub, priv, err := GenerateEd25519KeyPair()
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("torsion")
+	torsion := ed25519BatchTestEntry{pub, message, ed25519TorsionSignature(t, priv, message)}
+	if ed25519.Verify(pub, message, torsion.signature) {
+		t.Fatal("Expected ed25519.Verify to reject the signature.")
+	}
+
+	// The entry is valid in a batch that verifies and in one that does not.
+	clean := append(ed25519BatchEntries(t, 3), torsion)
+	if ok, failed := newEd25519Batch(clean).Verify(); !ok {
+		t.Errorf("Expected clean batch to verify, failed entries %v", failed)
+	}
+	dirty := append(ed25519BatchEntries(t, 3), torsion)
+	dirty[1].message = []byte("changed")
+	ok, failed := newEd25519Batch(dirty).Verify()
+	if ok || !reflect.DeepEqual(failed, []int{1}) {
+		t.Errorf("Got %v, expected %v", failed, []int{1})
+	}
+	torsionOnly := newEd25519Batch([]ed25519BatchTestEntry{torsion, torsion})
+	if ok, failed := torsionOnly.Verify(); !ok {
+		t.Errorf("Expected batch to verify, failed entries %v", failed)
+	}
+}
+
+func benchmarkEd25519Batch(b *testing.B, n int) {
+	entries := ed25519BatchEntries(b, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if ok, _ := newEd25519Batch(entries).Verify(); !ok {
+			b.Fatal("batch did not verify")
+		}
+	}
+}
+
+func benchmarkEd25519Loop(b *testing.B, n int) {
+	entries := ed25519BatchEntries(b, n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, entry := range entries {
+			verifier, _ := NewEd25519Verifier(entry.publicKey)
+			if !verifier.Verify(entry.message, entry.signature) {
+				b.Fatal("signature did not verify")
+			}
+		}
+	}
+}
+
+func BenchmarkEd25519BatchVerify8(b *testing.B)    { benchmarkEd25519Batch(b, 8) }
+func BenchmarkEd25519BatchVerify64(b *testing.B)   { benchmarkEd25519Batch(b, 64) }
+func BenchmarkEd25519BatchVerify1024(b *testing.B) { benchmarkEd25519Batch(b, 1024) }
+func BenchmarkEd25519LoopVerify8(b *testing.B)     { benchmarkEd25519Loop(b, 8) }
+func BenchmarkEd25519LoopVerify64(b *testing.B)    { benchmarkEd25519Loop(b, 64) }
+func BenchmarkEd25519LoopVerify1024(b *testing.B)  { benchmarkEd25519Loop(b, 1024) }
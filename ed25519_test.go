@@ -141,3 +141,151 @@ func TestEd25519PublicFromPrivate(t *testing.T) {
 		t.Errorf("Expected %x, pubkey was %x.", expected, pubKey)
 	}
 }
+
+// Test vectors from RFC 8032 sections 7.2 and 7.3, the private key is the
+// seed followed by the public key.
+var ed25519VariantTestCases = []struct {
+	name      string
+	suiteType string
+	seed      string
+	pubKey    string
+	context   string
+	message   string
+	signature string
+}{
+	{
+		"ph_abc",
+		"ed25519ph",
+		"833fe62409237b9d62ec77587520911e9a759cec1d19755b7da901b96dca3d42",
+		"ec172b93ad5e563bf4932c70e1245034c35467ef2efd4d64ebf819683467e2bf",
+		"",
+		"616263",
+		"98a70222f0b8121aa9d30f813d683f809e462b469c7ff87639499bb94e6dae4131f85042463c2a355a2003d062adf5aaa10b8c61e636062aaad11c2a26083406",
+	},
+	{
+		"ctx_foo",
+		"ed25519ctx",
+		"0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+		"dfc9425e4f968f7f0c29f0259cf5f9aed6851c2bb4ad8bfb860cfee0ab248292",
+		"666f6f",
+		"f726936d19c800494e3fdaff20b276a8",
+		"55a4cc2f70a54e04288c5f4cd1e45a7bb520b36292911876cada7323198dd87a8b36950b95130022907a7fb7c4e9b2d5f6cca685a587b4b21f4b888e4e7edb0d",
+	},
+	{
+		"ctx_bar",
+		"ed25519ctx",
+		"0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+		"dfc9425e4f968f7f0c29f0259cf5f9aed6851c2bb4ad8bfb860cfee0ab248292",
+		"626172",
+		"f726936d19c800494e3fdaff20b276a8",
+		"fc60d5872fc46b3aa69f8b5b4351d5808f92bcc044606db097abab6dbcb1aee3216c48e8b3b66431b5b186d1d28f8ee15a5ca2df6668346291c2043d4eb3e90d",
+	},
+	{
+		"ctx_foo_other_message",
+		"ed25519ctx",
+		"0305334e381af78f141cb666f6199f57bc3495335a256a95bd2a55bf546663f6",
+		"dfc9425e4f968f7f0c29f0259cf5f9aed6851c2bb4ad8bfb860cfee0ab248292",
+		"666f6f",
+		"508e9e6882b979fea900f62adceaca35",
+		"8b70c1cc8310e1de20ac53ce28ae6e7207f33c3295e03bb5c0732a1d20dc64908922a8b052cf99b7c4fe107a5abb5b2c4085ae75890d02df26269d8945f84b0b",
+	},
+	{
+		"ctx_foo_other_key",
+		"ed25519ctx",
+		"ab9c2853ce297ddab85c993b3ae14bcad39b2c682beabc27d6d4eb20711d6560",
+		"0f1d1274943b91415889152e893d80e93275a1fc0b65fd71b4b0dda10ad7d772",
+		"666f6f",
+		"f726936d19c800494e3fdaff20b276a8",
+		"21655b5f1aa965996b3f97b3c849eafba922a0a62992f73b3d1b73106a84ad85e9b86a7b6005ea868337ff2d20a7f5fbd4cd10b0be49a68da2b2e0dc0ad8960f",
+	},
+}
+
+func newEd25519VariantPair(t *testing.T, suiteType, seed, pubKey, context string) (Signer, Verifier) {
+	privKey, _ := FromHex(seed + pubKey)
+	pub, _ := FromHex(pubKey)
+	ctx, _ := FromHex(context)
+	var signer Signer
+	var verifier Verifier
+	var signErr, verErr error
+	if suiteType == "ed25519ph" {
+		signer, signErr = NewEd25519phSigner(privKey)
+		verifier, verErr = NewEd25519phVerifier(pub)
+	} else {
+		signer, signErr = NewEd25519ctxSigner(privKey, ctx)
+		verifier, verErr = NewEd25519ctxVerifier(pub, ctx)
+	}
+	if signErr != nil || verErr != nil {
+		t.Fatalf("Got a constructor error %v %v", signErr, verErr)
+	}
+	return signer, verifier
+}
+
+func TestEd25519Variants(t *testing.T) {
+	for _, tt := range ed25519VariantTestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			signer, verifier := newEd25519VariantPair(t, tt.suiteType, tt.seed, tt.pubKey, tt.context)
+			message, _ := FromHex(tt.message)
+			if signer.SuiteType() != tt.suiteType || verifier.SuiteType() != tt.suiteType {
+				t.Errorf("Got %s, expected %s", signer.SuiteType(), tt.suiteType)
+			}
+
+			signature, err := signer.Sign(message)
+			if err != nil {
+				t.Fatalf("Got a sign error %s", err)
+			}
+			if ToHex(signature) != tt.signature {
+				t.Errorf("Expected %s, signature was %x.", tt.signature, signature)
+			}
+			if !verifier.Verify(message, signature) || !signer.Verify(message, signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+			if verifier.Verify(append(message, 1), signature) {
+				t.Errorf("Expected signature over another message to not verify.")
+			}
+
+			// The same key as pure Ed25519 must not accept the signature.
+			pubKey, _ := FromHex(tt.pubKey)
+			pure, _ := NewEd25519Verifier(pubKey)
+			if pure.Verify(message, signature) {
+				t.Errorf("Expected pure Ed25519 to not verify signature.")
+			}
+		})
+	}
+}
+
+func TestEd25519ctxWrongContext(t *testing.T) {
+	tt := ed25519VariantTestCases[1]
+	_, verifier := newEd25519VariantPair(t, tt.suiteType, tt.seed, tt.pubKey, ToHex([]byte("bar")))
+	message, _ := FromHex(tt.message)
+	signature, _ := FromHex(tt.signature)
+	if verifier.Verify(message, signature) {
+		t.Errorf("Expected signature under another context to not verify.")
+	}
+}
+
+func TestEd25519ctxContextLength(t *testing.T) {
+	privKey, _ := FromHex(Ed25519PrivHex)
+	pubKey, _ := FromHex(Ed25519PubHex)
+	for _, context := range [][]byte{nil, make([]byte, 256)} {
+		if _, err := NewEd25519ctxSigner(privKey, context); err == nil {
+			t.Errorf("Expected error for %d byte context.", len(context))
+		}
+		if _, err := NewEd25519ctxVerifier(pubKey, context); err == nil {
+			t.Errorf("Expected error for %d byte context.", len(context))
+		}
+	}
+}
+
+func TestNewEd25519phKeySize(t *testing.T) {
+	key := []byte{1, 2, 3}
+	_, sigErr := NewEd25519phSigner(key)
+	_, verErr := NewEd25519phVerifier(key)
+
+	if sigErr == nil {
+		t.Errorf("Did not get signature error.")
+	}
+
+	if verErr == nil {
+		t.Errorf("Did not get verifier error.")
+	}
+}
@@ -0,0 +1,158 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+
+	"github.com/cloudflare/circl/ecc/goldilocks"
+	"github.com/cloudflare/circl/sign/ed448"
+)
+
+// Ed448 is the Edwards curve signature scheme over Curve448 described in
+// RFC 8032. It offers roughly 224 bits of security compared to the 128 bits
+// of ed25519. As with ed25519 the private key is the seed followed by the
+// public key.
+const (
+	// Ed448PrivateKeyLength Private key length for ed448
+	Ed448PrivateKeyLength = ed448.PrivateKeySize
+	// Ed448PublicKeyLength Public key length for ed448
+	Ed448PublicKeyLength = ed448.PublicKeySize
+	// Ed448SignatureLength Signature length for ed448
+	Ed448SignatureLength = ed448.SignatureSize
+	// Ed448SeedLength Length of the seed a private key is derived from
+	Ed448SeedLength = ed448.SeedSize
+)
+
+func init() {
+	mustRegisterSuite("ed448", NewEd448Signer, NewEd448Verifier)
+}
+
+// NewEd448Verifier constructor for ed448 Verifier
+func NewEd448Verifier(pubKey []byte) (Verifier, error) {
+	if len(pubKey) != Ed448PublicKeyLength {
+		return nil, fmt.Errorf("%w: key should be 57 bytes got %d", ErrInvalidPublicKey, len(pubKey))
+	}
+	return &ed448Verifier{
+		publicKey: pubKey,
+		suiteType: "ed448",
+	}, nil
+}
+
+// NewEd448Signer constructor for ed448 Signer
+func NewEd448Signer(privKey []byte) (Signer, error) {
+	if len(privKey) != Ed448PrivateKeyLength {
+		return nil, errors.New("key should be 114 bytes got " + fmt.Sprint(len(privKey)))
+	}
+
+	verifier, verErr := NewEd448Verifier(privKey[Ed448SeedLength:])
+	if verErr != nil {
+		return nil, verErr
+	}
+
+	return &ed448Signer{
+		privKey:  privKey,
+		verifier: verifier.(*ed448Verifier),
+	}, nil
+}
+
+// GenerateEd448KeyPair a valid Curve448 key pair.
+func GenerateEd448KeyPair() (pub []byte, priv []byte, err error) {
+	pubKey, privKey, err := ed448.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pubKey, privKey, nil
+}
+
+// Ed448PublicKeyFromPrivate return the public key associated with a private key for Curve448.
+func Ed448PublicKeyFromPrivate(privKey []byte) ([]byte, error) {
+	if len(privKey) != Ed448PrivateKeyLength {
+		return nil, errors.New("key should be 114 bytes got " + fmt.Sprint(len(privKey)))
+	}
+	// last 57 bytes of the private key are the public key
+	return privKey[Ed448SeedLength:], nil
+}
+
+// Ed448KeyPairFromSeed derives a Curve448 key pair from a 57 byte seed.
+func Ed448KeyPairFromSeed(seed []byte) (pub []byte, priv []byte, err error) {
+	if len(seed) != Ed448SeedLength {
+		return nil, nil, errors.New("invalid seed length, seed must be 57 bytes")
+	}
+	priv = ed448.NewKeyFromSeed(seed)
+	pub = priv[Ed448SeedLength:]
+	return pub, priv, nil
+}
+
+// ed448Verifier Verifies a signature using Curve448 and ed448
+type ed448Verifier struct {
+	publicKey ed448.PublicKey
+	suiteType string
+}
+
+func (s *ed448Verifier) SuiteType() string {
+	return s.suiteType
+}
+
+// PublicKey returns the public key, see PublicKeyHolder.
+func (s *ed448Verifier) PublicKey() []byte {
+	return append([]byte{}, s.publicKey...)
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *ed448Verifier) KeyID() string {
+	return KeyIDFromPublicKey(s.PublicKey())
+}
+
+func (s *ed448Verifier) Verify(toVerify []byte, signature []byte) bool {
+	return s.VerifyErr(toVerify, signature) == nil
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *ed448Verifier) VerifyErr(toVerify []byte, signature []byte) error {
+	if len(signature) != Ed448SignatureLength {
+		return fmt.Errorf(
+			"%w: expected %d bytes, got %d bytes",
+			ErrSignatureLength, Ed448SignatureLength, len(signature))
+	}
+	// Keys are only checked for their length when the verifier is created.
+	if _, err := goldilocks.FromBytes(s.publicKey); err != nil {
+		return fmt.Errorf("%w: not a point on the curve", ErrInvalidPublicKey)
+	}
+	if !ed448.Verify(s.publicKey, toVerify, signature, "") {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+type ed448Signer struct {
+	privKey  ed448.PrivateKey
+	verifier *ed448Verifier
+}
+
+func (s *ed448Signer) Sign(toSign []byte) ([]byte, error) {
+	return ed448.Sign(s.privKey, toSign, ""), nil
+}
+
+func (s *ed448Signer) Verify(toVerify []byte, signature []byte) bool {
+	return s.verifier.Verify(toVerify, signature)
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *ed448Signer) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifier.VerifyErr(toVerify, signature)
+}
+
+func (s *ed448Signer) SuiteType() string {
+	return s.verifier.SuiteType()
+}
+
+// PublicKey returns the public key, see PublicKeyHolder.
+func (s *ed448Signer) PublicKey() []byte {
+	return s.verifier.PublicKey()
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *ed448Signer) KeyID() string {
+	return s.verifier.KeyID()
+}
@@ -0,0 +1,177 @@
+package crypto
+
+import (
+	"reflect"
+	"testing"
+)
+
+// Test vectors from RFC 8032 section 7.4, the private key is the seed followed
+// by the public key.
+var ed448TestCases = []struct {
+	name      string
+	seed      string
+	pubKey    string
+	message   string
+	signature string
+}{
+	{
+		"blank",
+		"6c82a562cb808d10d632be89c8513ebf6c929f34ddfa8c9f63c9960ef6e348a3528c8a3fcc2f044e39a3fc5b94492f8f032e7549a20098f95b",
+		"5fd7449b59b461fd2ce787ec616ad46a1da1342485a70e1f8a0ea75d80e96778edf124769b46c7061bd6783df1e50f6cd1fa1abeafe8256180",
+		"",
+		"533a37f6bbe457251f023c0d88f976ae2dfb504a843e34d2074fd823d41a591f2b233f034f628281f2fd7a22ddd47d7828c59bd0a21bfd3980ff0d2028d4b18a9df63e006c5d1c2d345b925d8dc00b4104852db99ac5c7cdda8530a113a0f4dbb61149f05a7363268c71d95808ff2e652600",
+	},
+	{
+		"1_octet",
+		"c4eab05d357007c632f3dbb48489924d552b08fe0c353a0d4a1f00acda2c463afbea67c5e8d2877c5e3bc397a659949ef8021e954e0a12274e",
+		"43ba28f430cdff456ae531545f7ecd0ac834a55d9358c0372bfa0c6c6798c0866aea01eb00742802b8438ea4cb82169c235160627b4c3a9480",
+		"03",
+		"26b8f91727bd62897af15e41eb43c377efb9c610d48f2335cb0bd0087810f4352541b143c4b981b7e18f62de8ccdf633fc1bf037ab7cd779805e0dbcc0aae1cbcee1afb2e027df36bc04dcecbf154336c19f0af7e0a6472905e799f1953d2a0ff3348ab21aa4adafd1d234441cf807c03a00",
+	},
+	{
+		"11_octets",
+		"cd23d24f714274e744343237b93290f511f6425f98e64459ff203e8985083ffdf60500553abc0e05cd02184bdb89c4ccd67e187951267eb328",
+		"dcea9e78f35a1bf3499a831b10b86c90aac01cd84b67a0109b55a36e9328b1e365fce161d71ce7131a543ea4cb5f7e9f1d8b00696447001400",
+		"0c3e544074ec63b0265e0c",
+		"1f0a8888ce25e8d458a21130879b840a9089d999aaba039eaf3e3afa090a09d389dba82c4ff2ae8ac5cdfb7c55e94d5d961a29fe0109941e00b8dbdeea6d3b051068df7254c0cdc129cbe62db2dc957dbb47b51fd3f213fb8698f064774250a5028961c9bf8ffd973fe5d5c206492b140e00",
+	},
+	{
+		"12_octets",
+		"258cdd4ada32ed9c9ff54e63756ae582fb8fab2ac721f2c8e676a72768513d939f63dddb55609133f29adf86ec9929dccb52c1c5fd2ff7e21b",
+		"3ba16da0c6f2cc1f30187740756f5e798d6bc5fc015d7c63cc9510ee3fd44adc24d8e968b6e46e6f94d19b945361726bd75e149ef09817f580",
+		"64a65f3cdedcdd66811e2915",
+		"7eeeab7c4e50fb799b418ee5e3197ff6bf15d43a14c34389b59dd1a7b1b85b4ae90438aca634bea45e3a2695f1270f07fdcdf7c62b8efeaf00b45c2c96ba457eb1a8bf075a3db28e5c24f6b923ed4ad747c3c9e03c7079efb87cb110d3a99861e72003cbae6d6b8b827e4e6c143064ff3c00",
+	},
+	{
+		"64_octets",
+		"d65df341ad13e008567688baedda8e9dcdc17dc024974ea5b4227b6530e339bff21f99e68ca6968f3cca6dfe0fb9f4fab4fa135d5542ea3f01",
+		"df9705f58edbab802c7f8363cfe5560ab1c6132c20a9f1dd163483a26f8ac53a39d6808bf4a1dfbd261b099bb03b3fb50906cb28bd8a081f00",
+		"bd0f6a3747cd561bdddf4640a332461a4a30a12a434cd0bf40d766d9c6d458e5512204a30c17d1f50b5079631f64eb3112182da3005835461113718d1a5ef944",
+		"554bc2480860b49eab8532d2a533b7d578ef473eeb58c98bb2d0e1ce488a98b18dfde9b9b90775e67f47d4a1c3482058efc9f40d2ca033a0801b63d45b3b722ef552bad3b4ccb667da350192b61c508cf7b6b5adadc2c8d9a446ef003fb05cba5f30e88e36ec2703b349ca229c2670833900",
+	},
+}
+
+func TestEd448SuiteType(t *testing.T) {
+	signer := ed448Signer{
+		verifier: &ed448Verifier{suiteType: "Test"},
+	}
+	expected := "Test"
+	result := signer.SuiteType()
+	if expected != result {
+		t.Errorf("Got %s, expected %s", result, expected)
+	}
+}
+
+func TestEd448KeyPairFromSeed(t *testing.T) {
+	for _, tt := range ed448TestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			seed, _ := FromHex(tt.seed)
+			pub, priv, err := Ed448KeyPairFromSeed(seed)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ToHex(pub) != tt.pubKey || ToHex(priv) != tt.seed+tt.pubKey {
+				t.Errorf("key do not match pub key : %x, expected pub key : %s", pub, tt.pubKey)
+			}
+		})
+	}
+}
+
+func TestEd448KeyPairFromSeedBadLength(t *testing.T) {
+	_, _, err := Ed448KeyPairFromSeed([]byte{1, 2, 3})
+	if err == nil {
+		t.Errorf("Did not get seed length error.")
+	}
+}
+
+func TestNewEd448Signer(t *testing.T) {
+	for _, tt := range ed448TestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			privKey, _ := FromHex(tt.seed + tt.pubKey)
+			message, _ := FromHex(tt.message)
+			signer, err := NewEd448Signer(privKey)
+			if err != nil {
+				t.Fatalf("Got a constructor error %s", err)
+			}
+			if signer.SuiteType() != "ed448" {
+				t.Errorf("Expected suite type ed448 got %s", signer.SuiteType())
+			}
+
+			signature, err := signer.Sign(message)
+			if err != nil {
+				t.Fatalf("Got a sign error %s", err)
+			}
+			if ToHex(signature) != tt.signature {
+				t.Errorf("Expected %s, signature was %x.", tt.signature, signature)
+			}
+			if !signer.Verify(message, signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+		})
+	}
+}
+
+func TestNewEd448Verifier(t *testing.T) {
+	for _, tt := range ed448TestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			pubKey, _ := FromHex(tt.pubKey)
+			message, _ := FromHex(tt.message)
+			signature, _ := FromHex(tt.signature)
+			verifier, err := NewEd448Verifier(pubKey)
+			if err != nil {
+				t.Fatalf("Got a constructor error %s", err)
+			}
+			if !verifier.Verify(message, signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+			if verifier.Verify(append(message, 1), signature) {
+				t.Errorf("Expected signature over another message to not verify.")
+			}
+			if verifier.Verify(message, signature[1:]) {
+				t.Errorf("Expected short signature to not verify.")
+			}
+		})
+	}
+}
+
+func TestNewEd448KeySize(t *testing.T) {
+	key := []byte{1, 2, 3}
+	_, sigErr := NewEd448Signer(key)
+	_, verErr := NewEd448Verifier(key)
+
+	if sigErr == nil {
+		t.Errorf("Did not get signature error.")
+	}
+
+	if verErr == nil {
+		t.Errorf("Did not get verifier error.")
+	}
+}
+
+func TestEd448PublicFromPrivate(t *testing.T) {
+	tt := ed448TestCases[0]
+	privKey, _ := FromHex(tt.seed + tt.pubKey)
+
+	pubKey, _ := Ed448PublicKeyFromPrivate(privKey)
+
+	expected, _ := FromHex(tt.pubKey)
+	if !reflect.DeepEqual(pubKey, expected) {
+		t.Errorf("Expected %x, pubkey was %x.", expected, pubKey)
+	}
+}
+
+func TestGenerateEd448KeyPair(t *testing.T) {
+	pub, priv, err := GenerateEd448KeyPair()
+	if err != nil {
+		t.Fatalf("Got a key generation error %s", err)
+	}
+	signer, _ := NewEd448Signer(priv)
+	verifier, err := NewEd448Verifier(pub)
+	if err != nil {
+		t.Fatalf("Got a constructor error %s", err)
+	}
+	signature, _ := signer.Sign([]byte{1, 2, 3})
+	if !verifier.Verify([]byte{1, 2, 3}, signature) {
+		t.Errorf("Expected to verify signature.")
+	}
+}
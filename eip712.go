@@ -0,0 +1,503 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// EIP-712 hashes structured data instead of an opaque byte string, so wallets
+// can show users what they are signing. The typed data is described with the
+// JSON object taken by eth_signTypedData_v4:
+//
+//	{
+//	  "types": {
+//	    "EIP712Domain": [{"name": "name", "type": "string"}, ...],
+//	    "Mail": [{"name": "from", "type": "Person"}, ...],
+//	    ...
+//	  },
+//	  "primaryType": "Mail",
+//	  "domain": {"name": "Ether Mail", ...},
+//	  "message": {"from": {...}, ...}
+//	}
+//
+// The digest that is signed is
+// keccak256(0x19 || 0x01 || DomainSeparator() || HashStruct(primaryType, message)).
+
+const eip712DomainType = "EIP712Domain"
+
+// Struct type names appear in the encoded type, so they must not contain the
+// characters used to separate members.
+var eip712TypeName = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypedDataField is a member of an EIP-712 struct type.
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is an EIP-712 typed data description. Values in Domain and
+// Message follow the JSON encoding used by wallets: integers are JSON numbers,
+// decimal strings or 0x prefixed hex strings, bytes and bytesN are 0x prefixed
+// hex strings, addresses are hex strings, structs are objects and arrays are
+// arrays.
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// ParseTypedData decodes a JSON typed data description and checks that the
+// types it declares are well formed. JSON numbers are kept as json.Number so
+// that large integers are not rounded.
+func ParseTypedData(data []byte) (*TypedData, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	typedData := &TypedData{}
+	if err := decoder.Decode(typedData); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTypedData, err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%w: unexpected data after JSON object", ErrTypedData)
+	}
+	if err := typedData.validate(); err != nil {
+		return nil, err
+	}
+	return typedData, nil
+}
+
+func (t *TypedData) validate() error {
+	if _, ok := t.Types[eip712DomainType]; !ok {
+		return fmt.Errorf("%w: missing %s type", ErrTypedData, eip712DomainType)
+	}
+	if _, ok := t.Types[t.PrimaryType]; !ok {
+		return fmt.Errorf("%w: unknown primary type %q", ErrTypedData, t.PrimaryType)
+	}
+	if t.Domain == nil {
+		return fmt.Errorf("%w: missing domain", ErrTypedData)
+	}
+	if t.Message == nil && t.PrimaryType != eip712DomainType {
+		return fmt.Errorf("%w: missing message", ErrTypedData)
+	}
+	for name, fields := range t.Types {
+		if !eip712TypeName.MatchString(name) || isEIP712AtomicType(name) {
+			return fmt.Errorf("%w: invalid type name %q", ErrTypedData, name)
+		}
+		names := make(map[string]bool, len(fields))
+		for _, field := range fields {
+			if !eip712TypeName.MatchString(field.Name) || names[field.Name] {
+				return fmt.Errorf("%w: invalid or duplicate member %s.%q", ErrTypedData, name, field.Name)
+			}
+			names[field.Name] = true
+			if err := t.checkType(field.Type); err != nil {
+				return fmt.Errorf("%w: %s.%s: %s", ErrTypedData, name, field.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (t *TypedData) checkType(typ string) error {
+	if elem, _, isArray, err := parseEIP712ArrayType(typ); isArray || err != nil {
+		if err != nil {
+			return err
+		}
+		return t.checkType(elem)
+	}
+	if _, ok := t.Types[typ]; ok || isEIP712AtomicType(typ) {
+		return nil
+	}
+	return fmt.Errorf("unknown type %q", typ)
+}
+
+// EncodeType returns the encoding of a struct type: the type itself followed
+// by the struct types it references, sorted by name, e.g.
+// "Mail(Person from,Person to,string contents)Person(string name,address wallet)".
+func (t *TypedData) EncodeType(primaryType string) (string, error) {
+	if _, ok := t.Types[primaryType]; !ok {
+		return "", fmt.Errorf("%w: unknown type %q", ErrTypedData, primaryType)
+	}
+	dependencies := make(map[string]bool)
+	t.collectDependencies(primaryType, dependencies)
+	delete(dependencies, primaryType)
+	names := make([]string, 0, len(dependencies))
+	for name := range dependencies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var encoded strings.Builder
+	for _, name := range append([]string{primaryType}, names...) {
+		encoded.WriteString(name)
+		encoded.WriteByte('(')
+		for i, field := range t.Types[name] {
+			if i > 0 {
+				encoded.WriteByte(',')
+			}
+			encoded.WriteString(field.Type)
+			encoded.WriteByte(' ')
+			encoded.WriteString(field.Name)
+		}
+		encoded.WriteByte(')')
+	}
+	return encoded.String(), nil
+}
+
+func (t *TypedData) collectDependencies(typ string, dependencies map[string]bool) {
+	for {
+		elem, _, isArray, err := parseEIP712ArrayType(typ)
+		if !isArray || err != nil {
+			break
+		}
+		typ = elem
+	}
+	fields, ok := t.Types[typ]
+	if !ok || dependencies[typ] {
+		return
+	}
+	dependencies[typ] = true
+	for _, field := range fields {
+		t.collectDependencies(field.Type, dependencies)
+	}
+}
+
+// TypeHash returns the Keccak256 hash of EncodeType(primaryType).
+func (t *TypedData) TypeHash(primaryType string) ([]byte, error) {
+	encodedType, err := t.EncodeType(primaryType)
+	if err != nil {
+		return nil, err
+	}
+	return (&Keccak256Hasher{}).Hash([]byte(encodedType)), nil
+}
+
+// EncodeData returns the type hash of primaryType followed by the 32 byte
+// encoding of each of its members in data. Members that are missing from data
+// and values that are not members of the type are errors.
+func (t *TypedData) EncodeData(primaryType string, data map[string]interface{}) ([]byte, error) {
+	encoded, err := t.encodeData(primaryType, data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrTypedData, err)
+	}
+	return encoded, nil
+}
+
+func (t *TypedData) encodeData(primaryType string, data map[string]interface{}) ([]byte, error) {
+	typeHash, err := t.TypeHash(primaryType)
+	if err != nil {
+		return nil, err
+	}
+	fields := t.Types[primaryType]
+	for name := range data {
+		if !hasTypedDataField(fields, name) {
+			return nil, fmt.Errorf("%s.%s: not a member of the type", primaryType, name)
+		}
+	}
+	encoded := typeHash
+	for _, field := range fields {
+		value, ok := data[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s.%s: missing value", primaryType, field.Name)
+		}
+		encodedValue, err := t.encodeValue(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %s", primaryType, field.Name, err)
+		}
+		encoded = append(encoded, encodedValue...)
+	}
+	return encoded, nil
+}
+
+func hasTypedDataField(fields []TypedDataField, name string) bool {
+	for _, field := range fields {
+		if field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Encodes a member value as 32 bytes. Structs, arrays and dynamic types are
+// encoded as the Keccak256 hash of their contents.
+func (t *TypedData) encodeValue(typ string, value interface{}) ([]byte, error) {
+	hasher := &Keccak256Hasher{}
+	elem, length, isArray, err := parseEIP712ArrayType(typ)
+	if err != nil {
+		return nil, err
+	}
+	if isArray {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an array for %s", typ)
+		}
+		if length >= 0 && len(items) != length {
+			return nil, fmt.Errorf("expected %d items for %s, got %d", length, typ, len(items))
+		}
+		encoded := make([]byte, 0, 32*len(items))
+		for i, item := range items {
+			encodedItem, err := t.encodeValue(elem, item)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %s", i, err)
+			}
+			encoded = append(encoded, encodedItem...)
+		}
+		return hasher.Hash(encoded), nil
+	}
+	if _, ok := t.Types[typ]; ok {
+		data, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("expected an object for %s", typ)
+		}
+		encoded, err := t.encodeData(typ, data)
+		if err != nil {
+			return nil, err
+		}
+		return hasher.Hash(encoded), nil
+	}
+	return encodeEIP712AtomicValue(typ, value)
+}
+
+// HashStruct returns the Keccak256 hash of EncodeData(primaryType, data).
+func (t *TypedData) HashStruct(primaryType string, data map[string]interface{}) ([]byte, error) {
+	encoded, err := t.EncodeData(primaryType, data)
+	if err != nil {
+		return nil, err
+	}
+	return (&Keccak256Hasher{}).Hash(encoded), nil
+}
+
+// DomainSeparator returns the hash of the domain as an EIP712Domain struct.
+func (t *TypedData) DomainSeparator() ([]byte, error) {
+	return t.HashStruct(eip712DomainType, t.Domain)
+}
+
+// Hash returns the EIP-712 digest of the typed data, the value signed by
+// eth_signTypedData_v4. When the primary type is EIP712Domain only the domain
+// separator is hashed.
+func (t *TypedData) Hash() ([]byte, error) {
+	if err := t.validate(); err != nil {
+		return nil, err
+	}
+	domainSeparator, err := t.DomainSeparator()
+	if err != nil {
+		return nil, err
+	}
+	hasher := &Keccak256Hasher{}
+	if t.PrimaryType == eip712DomainType {
+		return hasher.Hash([]byte{0x19, 0x01}, domainSeparator), nil
+	}
+	messageHash, err := t.HashStruct(t.PrimaryType, t.Message)
+	if err != nil {
+		return nil, err
+	}
+	return hasher.Hash([]byte{0x19, 0x01}, domainSeparator, messageHash), nil
+}
+
+// SignTypedData signs the EIP-712 digest of typedData with an
+// ecdsa_secp256k1_keccak256 signer, as eth_signTypedData_v4 does. The
+// signature is R || S || V with V 0 or 1, wallets return V + 27.
+func SignTypedData(signer Signer, typedData *TypedData) ([]byte, error) {
+	digest, err := typedData.Hash()
+	if err != nil {
+		return nil, err
+	}
+	return signEthereumDigest(signer, digest)
+}
+
+// VerifyTypedData verifies an eth_signTypedData_v4 signature over typedData,
+// see VerifyEthereumPersonalMessage.
+func VerifyTypedData(verifier Verifier, typedData *TypedData, signature []byte) bool {
+	digest, err := typedData.Hash()
+	if err != nil {
+		return false
+	}
+	return verifyEthereumDigest(verifier, digest, signature)
+}
+
+// Splits T[] and T[n] into the element type T and the length, -1 for
+// dynamic arrays.
+func parseEIP712ArrayType(typ string) (elem string, length int, isArray bool, err error) {
+	if !strings.HasSuffix(typ, "]") {
+		return typ, 0, false, nil
+	}
+	open := strings.LastIndexByte(typ, '[')
+	if open <= 0 {
+		return "", 0, false, fmt.Errorf("invalid array type %q", typ)
+	}
+	size := typ[open+1 : len(typ)-1]
+	if size == "" {
+		return typ[:open], -1, true, nil
+	}
+	length, err = strconv.Atoi(size)
+	if err != nil || length <= 0 || size[0] == '0' {
+		return "", 0, false, fmt.Errorf("invalid array length in %q", typ)
+	}
+	return typ[:open], length, true, nil
+}
+
+func isEIP712AtomicType(typ string) bool {
+	switch typ {
+	case "address", "bool", "string", "bytes":
+		return true
+	}
+	if _, ok := eip712FixedBytesLength(typ); ok {
+		return true
+	}
+	_, _, ok := eip712IntegerBits(typ)
+	return ok
+}
+
+// Returns N for bytes1 to bytes32.
+func eip712FixedBytesLength(typ string) (int, bool) {
+	if !strings.HasPrefix(typ, "bytes") {
+		return 0, false
+	}
+	size := typ[len("bytes"):]
+	length, err := strconv.Atoi(size)
+	if err != nil || length < 1 || length > 32 || size[0] == '0' {
+		return 0, false
+	}
+	return length, true
+}
+
+// Returns N for uint8 to uint256 and int8 to int256, in steps of 8.
+func eip712IntegerBits(typ string) (bits int, signed bool, ok bool) {
+	size := strings.TrimPrefix(typ, "u")
+	signed = size == typ
+	if !strings.HasPrefix(size, "int") {
+		return 0, false, false
+	}
+	size = size[len("int"):]
+	bits, err := strconv.Atoi(size)
+	if err != nil || bits < 8 || bits > 256 || bits%8 != 0 || size[0] == '0' {
+		return 0, false, false
+	}
+	return bits, signed, true
+}
+
+func encodeEIP712AtomicValue(typ string, value interface{}) ([]byte, error) {
+	hasher := &Keccak256Hasher{}
+	encoded := make([]byte, 32)
+	switch typ {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("expected a string")
+		}
+		return hasher.Hash([]byte(s)), nil
+	case "bytes":
+		b, err := decodeEIP712Bytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return hasher.Hash(b), nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, errors.New("expected a boolean")
+		}
+		if b {
+			encoded[31] = 1
+		}
+		return encoded, nil
+	case "address":
+		s, ok := value.(string)
+		if !ok {
+			return nil, errors.New("expected an address string")
+		}
+		address, err := EthereumAddressFromHex(s)
+		if err != nil {
+			return nil, err
+		}
+		copy(encoded[32-EthereumAddressLength:], address)
+		return encoded, nil
+	}
+	if length, ok := eip712FixedBytesLength(typ); ok {
+		b, err := decodeEIP712Bytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != length {
+			return nil, fmt.Errorf("expected %d bytes for %s, got %d", length, typ, len(b))
+		}
+		copy(encoded, b)
+		return encoded, nil
+	}
+	if bits, signed, ok := eip712IntegerBits(typ); ok {
+		n, err := parseEIP712Integer(value)
+		if err != nil {
+			return nil, err
+		}
+		if !eip712IntegerInRange(n, bits, signed) {
+			return nil, fmt.Errorf("%s out of range for %s", n, typ)
+		}
+		if n.Sign() < 0 {
+			// Two's complement in 256 bits.
+			n = new(big.Int).Add(n, new(big.Int).Lsh(big.NewInt(1), 256))
+		}
+		return n.FillBytes(encoded), nil
+	}
+	return nil, fmt.Errorf("unknown type %q", typ)
+}
+
+func decodeEIP712Bytes(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok || !strings.HasPrefix(s, "0x") {
+		return nil, errors.New("expected a 0x prefixed hex string")
+	}
+	b, err := FromHex(s[2:])
+	if err != nil {
+		return nil, ErrSyntax
+	}
+	return b, nil
+}
+
+// Integers may be JSON numbers, decimal strings or 0x prefixed hex strings,
+// optionally negative.
+func parseEIP712Integer(value interface{}) (*big.Int, error) {
+	var s string
+	switch v := value.(type) {
+	case json.Number:
+		s = v.String()
+	case string:
+		s = v
+	case *big.Int:
+		return v, nil
+	case float64:
+		return nil, errors.New("integer decoded as float64, decode JSON with UseNumber")
+	default:
+		return nil, errors.New("expected an integer")
+	}
+	digits := strings.TrimPrefix(s, "-")
+	base := 10
+	if strings.HasPrefix(digits, "0x") {
+		digits = digits[2:]
+		base = 16
+	}
+	n, ok := new(big.Int).SetString(digits, base)
+	if !ok || strings.HasPrefix(digits, "+") || strings.HasPrefix(digits, "-") {
+		return nil, fmt.Errorf("invalid integer %q", s)
+	}
+	if strings.HasPrefix(s, "-") {
+		n.Neg(n)
+	}
+	return n, nil
+}
+
+func eip712IntegerInRange(n *big.Int, bits int, signed bool) bool {
+	if !signed {
+		return n.Sign() >= 0 && n.BitLen() <= bits
+	}
+	if n.Sign() >= 0 {
+		return n.BitLen() < bits
+	}
+	// -2^(bits-1) is the smallest value, so -n - 1 must fit in bits - 1.
+	return new(big.Int).Sub(new(big.Int).Neg(n), big.NewInt(1)).BitLen() < bits
+}
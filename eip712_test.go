@@ -0,0 +1,304 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// The example from the EIP-712 specification.
+const eip712MailJSON = `{
+  "types": {
+    "EIP712Domain": [
+      {"name": "name", "type": "string"},
+      {"name": "version", "type": "string"},
+      {"name": "chainId", "type": "uint256"},
+      {"name": "verifyingContract", "type": "address"}
+    ],
+    "Person": [
+      {"name": "name", "type": "string"},
+      {"name": "wallet", "type": "address"}
+    ],
+    "Mail": [
+      {"name": "from", "type": "Person"},
+      {"name": "to", "type": "Person"},
+      {"name": "contents", "type": "string"}
+    ]
+  },
+  "primaryType": "Mail",
+  "domain": {
+    "name": "Ether Mail",
+    "version": "1",
+    "chainId": 1,
+    "verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+  },
+  "message": {
+    "from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+    "to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+    "contents": "Hello, Bob!"
+  }
+}`
+
+// Nested struct arrays, fixed length arrays, arrays of arrays and the other
+// atomic types.
+const eip712GroupJSON = `{
+  "types": {
+    "EIP712Domain": [
+      {"name": "name", "type": "string"},
+      {"name": "chainId", "type": "uint256"},
+      {"name": "salt", "type": "bytes32"}
+    ],
+    "Person": [
+      {"name": "name", "type": "string"},
+      {"name": "wallets", "type": "address[]"}
+    ],
+    "Group": [
+      {"name": "name", "type": "string"},
+      {"name": "members", "type": "Person[]"},
+      {"name": "admins", "type": "Person[2]"},
+      {"name": "scores", "type": "int16[][]"},
+      {"name": "active", "type": "bool"},
+      {"name": "data", "type": "bytes"},
+      {"name": "nonce", "type": "uint64"}
+    ]
+  },
+  "primaryType": "Group",
+  "domain": {
+    "name": "Groups",
+    "chainId": "0x89",
+    "salt": "0x0102030405060708091011121314151617181920212223242526272829303132"
+  },
+  "message": {
+    "name": "Friends",
+    "members": [
+      {"name": "Alice", "wallets": [
+        "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826",
+        "0xDeaDbeefdEAdbeefdEadbEEFdeadbeEFdEaDbeeF"
+      ]},
+      {"name": "Bob", "wallets": []}
+    ],
+    "admins": [
+      {"name": "Carol", "wallets": ["0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"]},
+      {"name": "Dave", "wallets": []}
+    ],
+    "scores": [[1, -2, 32767], [-32768]],
+    "active": true,
+    "data": "0xdeadbeef",
+    "nonce": "18446744073709551615"
+  }
+}`
+
+// Expected values computed with go-ethereum's apitypes package, signatures
+// by the Secp256k1PrivHex key.
+var eip712TestCases = []struct {
+	name            string
+	typedData       string
+	encodedType     string
+	domainSeparator string
+	messageHash     string
+	digest          string
+	signature       string
+}{
+	{
+		"mail",
+		eip712MailJSON,
+		"Mail(Person from,Person to,string contents)Person(string name,address wallet)",
+		"f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f",
+		"c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e",
+		"be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2",
+		"abda9e128bc0423d9f0b7e26b9333d30951a78ae470cea25c504d4b5c5046b2718f3648b461d062c38663795a017b0716eff86dc5adb8d267c699fa4d37e5b2f01",
+	},
+	{
+		"group",
+		eip712GroupJSON,
+		"Group(string name,Person[] members,Person[2] admins,int16[][] scores,bool active,bytes data,uint64 nonce)Person(string name,address[] wallets)",
+		"a30f91f10b0107c66999a66f67780cf65d08a5914b76cc450cb930830b3c6834",
+		"866dff3241b3c03aa0632f1f47cd074f54dd3cb4d6a6f3599cf3fec92ccd8bfc",
+		"258872317fe87ad1181c4bd63a88e7697f1efc50f7868fd6f738683811e37c33",
+		"9f8385ee22dd1fd42eb63ea7819023eb1e857f084c4b6fe2c6908e556fcd89707e0f86cfaa4f9ecd87e365e7b178c7de15660d21ce1bd4e8f5b1461e1c76218801",
+	},
+}
+
+func TestTypedDataHash(t *testing.T) {
+	for _, tt := range eip712TestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			typedData, err := ParseTypedData([]byte(tt.typedData))
+			if err != nil {
+				t.Fatalf("Error parsing typed data: %s", err)
+			}
+			encodedType, err := typedData.EncodeType(typedData.PrimaryType)
+			if err != nil {
+				t.Fatalf("Error encoding type: %s", err)
+			}
+			if encodedType != tt.encodedType {
+				t.Errorf("Got %s, expected %s", encodedType, tt.encodedType)
+			}
+
+			domainSeparator, err := typedData.DomainSeparator()
+			if err != nil {
+				t.Fatalf("Error hashing domain: %s", err)
+			}
+			if ToHex(domainSeparator) != tt.domainSeparator {
+				t.Errorf("Got %x, expected %s", domainSeparator, tt.domainSeparator)
+			}
+			messageHash, err := typedData.HashStruct(typedData.PrimaryType, typedData.Message)
+			if err != nil {
+				t.Fatalf("Error hashing message: %s", err)
+			}
+			if ToHex(messageHash) != tt.messageHash {
+				t.Errorf("Got %x, expected %s", messageHash, tt.messageHash)
+			}
+			digest, err := typedData.Hash()
+			if err != nil {
+				t.Fatalf("Error hashing typed data: %s", err)
+			}
+			if ToHex(digest) != tt.digest {
+				t.Errorf("Got %x, expected %s", digest, tt.digest)
+			}
+		})
+	}
+}
+
+func TestSignTypedData(t *testing.T) {
+	privKey, _ := FromHex(Secp256k1PrivHex)
+	signer, _ := NewSecp256k1Keccak256Signer(privKey)
+	address, _ := EthereumAddressFromHex(Secp256k1AddressHex)
+	addressVerifier, _ := NewSecp256k1Keccak256AddressVerifier(address)
+
+	for _, tt := range eip712TestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			typedData, _ := ParseTypedData([]byte(tt.typedData))
+			signature, err := SignTypedData(signer, typedData)
+			if err != nil {
+				t.Fatalf("Error signing: %s", err)
+			}
+			if ToHex(signature) != tt.signature {
+				t.Errorf("Got %x, expected %s", signature, tt.signature)
+			}
+			if !VerifyTypedData(signer, typedData, signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+			if !VerifyTypedData(addressVerifier, typedData, signature) {
+				t.Errorf("Expected address verifier to verify signature.")
+			}
+
+			walletSignature := append([]byte{}, signature...)
+			walletSignature[64] += 27
+			if !VerifyTypedData(addressVerifier, typedData, walletSignature) {
+				t.Errorf("Expected to verify signature with V + 27.")
+			}
+
+			typedData.Domain["name"] = "Other"
+			if VerifyTypedData(addressVerifier, typedData, signature) {
+				t.Errorf("Expected signature for another domain to not verify.")
+			}
+		})
+	}
+}
+
+func TestSignTypedDataWrongSuite(t *testing.T) {
+	privKey, _ := FromHex(Ed25519PrivHex)
+	signer, _ := NewEd25519Signer(privKey)
+	typedData, _ := ParseTypedData([]byte(eip712MailJSON))
+	_, err := SignTypedData(signer, typedData)
+	if !errors.Is(err, ErrUnexpectedSuite) {
+		t.Errorf("Expected ErrUnexpectedSuite, got %v", err)
+	}
+}
+
+func TestTypedDataDomainPrimaryType(t *testing.T) {
+	typedData, _ := ParseTypedData([]byte(eip712MailJSON))
+	typedData.PrimaryType = "EIP712Domain"
+	typedData.Message = nil
+	digest, err := typedData.Hash()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	domainSeparator, _ := typedData.DomainSeparator()
+	expected := (&Keccak256Hasher{}).Hash([]byte{0x19, 0x01}, domainSeparator)
+	if !reflect.DeepEqual(digest, expected) {
+		t.Errorf("Got %x, expected %x", digest, expected)
+	}
+}
+
+func TestParseTypedDataInvalid(t *testing.T) {
+	badTypedData := []struct {
+		name      string
+		typedData string
+	}{
+		{"not_json", `{"types":`},
+		{"trailing_data", eip712MailJSON + `{}`},
+		{"missing_domain_type", `{"types":{"Mail":[]},"primaryType":"Mail","domain":{},"message":{}}`},
+		{"unknown_primary_type", `{"types":{"EIP712Domain":[]},"primaryType":"Mail","domain":{},"message":{}}`},
+		{"missing_message", `{"types":{"EIP712Domain":[],"Mail":[]},"primaryType":"Mail","domain":{}}`},
+		{"unknown_member_type", `{"types":{"EIP712Domain":[],"Mail":[{"name":"to","type":"Persn"}]},"primaryType":"Mail","domain":{},"message":{}}`},
+		{"duplicate_member", `{"types":{"EIP712Domain":[],"Mail":[{"name":"a","type":"bool"},{"name":"a","type":"bool"}]},"primaryType":"Mail","domain":{},"message":{}}`},
+		{"bad_type_name", `{"types":{"EIP712Domain":[],"Ma(il":[]},"primaryType":"Ma(il","domain":{},"message":{}}`},
+		{"atomic_type_name", `{"types":{"EIP712Domain":[],"uint8":[]},"primaryType":"uint8","domain":{},"message":{}}`},
+		{"bad_integer_size", `{"types":{"EIP712Domain":[],"Mail":[{"name":"a","type":"uint7"}]},"primaryType":"Mail","domain":{},"message":{}}`},
+		{"bad_bytes_size", `{"types":{"EIP712Domain":[],"Mail":[{"name":"a","type":"bytes33"}]},"primaryType":"Mail","domain":{},"message":{}}`},
+		{"bad_array_length", `{"types":{"EIP712Domain":[],"Mail":[{"name":"a","type":"bool[0]"}]},"primaryType":"Mail","domain":{},"message":{}}`},
+	}
+	for _, tt := range badTypedData {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTypedData([]byte(tt.typedData))
+			if !errors.Is(err, ErrTypedData) {
+				t.Errorf("Expected ErrTypedData, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTypedDataInvalidValues(t *testing.T) {
+	const types = `{"EIP712Domain":[],"Value":[{"name":"v","type":"%s"}]}`
+	badValues := []struct {
+		typ   string
+		value string
+	}{
+		{"uint8", `256`},
+		{"uint8", `-1`},
+		{"int8", `128`},
+		{"int8", `-129`},
+		{"uint256", `1.5`},
+		{"uint256", `"1e3"`},
+		{"uint256", `true`},
+		{"bool", `"true"`},
+		{"address", `"0x1234"`},
+		{"address", `"0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD827"`},
+		{"address", `"0xcD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"`},
+		{"bytes", `"deadbeef"`},
+		{"bytes", `"0xdeadbee"`},
+		{"bytes4", `"0xdeadbeefaa"`},
+		{"bytes4", `"0xdead"`},
+		{"string", `1`},
+		{"bool[2]", `[true]`},
+		{"bool[]", `true`},
+	}
+	for _, tt := range badValues {
+		t.Run(tt.typ+"_"+tt.value, func(t *testing.T) {
+			typedData, err := ParseTypedData([]byte(`{"types":` + fmt.Sprintf(types, tt.typ) +
+				`,"primaryType":"Value","domain":{},"message":{"v":` + tt.value + `}}`))
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if _, err := typedData.Hash(); !errors.Is(err, ErrTypedData) {
+				t.Errorf("Expected ErrTypedData, got %v", err)
+			}
+		})
+	}
+}
+
+func TestTypedDataMessageMembers(t *testing.T) {
+	typedData, _ := ParseTypedData([]byte(eip712MailJSON))
+	typedData.Message["extra"] = "not signed"
+	if _, err := typedData.Hash(); !errors.Is(err, ErrTypedData) {
+		t.Errorf("Expected ErrTypedData for extra member, got %v", err)
+	}
+
+	typedData, _ = ParseTypedData([]byte(eip712MailJSON))
+	delete(typedData.Message, "contents")
+	if _, err := typedData.Hash(); !errors.Is(err, ErrTypedData) {
+		t.Errorf("Expected ErrTypedData for missing member, got %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// An Envelope carries a signature together with what is needed to verify it
+// later: the suite type, an identifier for the signer's key and attributes
+// that are signed along with the message. The signature is not over the
+// message alone but over
+//
+//	"crypto-envelope-v1" || 0x00 || suite || key ID || attributes || message
+//
+// with the fields before the message length prefixed as in the binary
+// encoding. Binding the suite and key ID stops a signature from being
+// presented as one of another suite or key.
+//
+// The binary encoding is a version byte followed by
+//
+//	suite length (1 byte) || suite
+//	key ID length (2 bytes) || key ID
+//	attribute flags (1 byte) || signing time (8 bytes) || content type length (2 bytes) || content type
+//	signature length (4 bytes) || signature
+//
+// where the signing time and content type are only present when their flag
+// is set. Lengths and times are big endian.
+
+// EnvelopeVersion Version byte of the binary envelope encoding
+const EnvelopeVersion = 1
+
+const envelopeContext = "crypto-envelope-v1\x00"
+
+const (
+	envelopeHasSigningTime = 1 << iota
+	envelopeHasContentType
+)
+
+// EnvelopeAttributes are optional attributes signed along with the message.
+// Zero values are left out.
+type EnvelopeAttributes struct {
+	// SigningTime is when the signature was created, kept to the nanosecond
+	// in UTC.
+	SigningTime time.Time
+	// ContentType describes the signed message, e.g. a media type.
+	ContentType string
+}
+
+// Envelope is a self-describing signature, see SignEnvelope and
+// VerifyEnvelope.
+type Envelope struct {
+	SuiteType  string
+	KeyID      string
+	Signature  []byte
+	Attributes EnvelopeAttributes
+}
+
+// PublicKeyResolver returns the public key with the given identifier, in the
+// format taken by the suite's verifier constructor.
+type PublicKeyResolver func(suiteType string, keyID string) ([]byte, error)
+
+// SignEnvelope signs message and its attributes, returning an envelope that
+// names the signer's suite and keyID.
+func SignEnvelope(
+	signer Signer,
+	keyID string,
+	message []byte,
+	attributes EnvelopeAttributes,
+) (*Envelope, error) {
+	if !attributes.SigningTime.IsZero() {
+		attributes.SigningTime = attributes.SigningTime.UTC().Round(0)
+	}
+	envelope := &Envelope{
+		SuiteType:  signer.SuiteType(),
+		KeyID:      keyID,
+		Attributes: attributes,
+	}
+	toSign, err := envelope.signingInput(message)
+	if err != nil {
+		return nil, err
+	}
+	envelope.Signature, err = signer.Sign(toSign)
+	if err != nil {
+		return nil, err
+	}
+	return envelope, nil
+}
+
+// VerifyEnvelope verifies an envelope's signature over message. The public
+// key is looked up with resolve and the verifier is built for the envelope's
+// suite with NewVerifierForSuite. An error is returned when no verifier can be
+// built, false when the signature does not verify.
+func VerifyEnvelope(envelope *Envelope, message []byte, resolve PublicKeyResolver) (bool, error) {
+	pubKey, err := resolve(envelope.SuiteType, envelope.KeyID)
+	if err != nil {
+		return false, err
+	}
+	verifier, err := NewVerifierForSuite(envelope.SuiteType, pubKey)
+	if err != nil {
+		return false, err
+	}
+	toVerify, err := envelope.signingInput(message)
+	if err != nil {
+		return false, err
+	}
+	return verifier.Verify(toVerify, envelope.Signature), nil
+}
+
+func (e *Envelope) signingInput(message []byte) ([]byte, error) {
+	header, err := e.appendHeader([]byte(envelopeContext))
+	if err != nil {
+		return nil, err
+	}
+	return append(header, message...), nil
+}
+
+// Appends the suite, key ID and attributes.
+func (e *Envelope) appendHeader(b []byte) ([]byte, error) {
+	if e.SuiteType == "" || len(e.SuiteType) > 0xff {
+		return nil, fmt.Errorf("%w: suite type must be 1 to 255 bytes", ErrSignatureEncoding)
+	}
+	if len(e.KeyID) > 0xffff {
+		return nil, fmt.Errorf("%w: key ID longer than 65535 bytes", ErrSignatureEncoding)
+	}
+	if len(e.Attributes.ContentType) > 0xffff {
+		return nil, fmt.Errorf("%w: content type longer than 65535 bytes", ErrSignatureEncoding)
+	}
+	b = append(b, byte(len(e.SuiteType)))
+	b = append(b, e.SuiteType...)
+	b = binary.BigEndian.AppendUint16(b, uint16(len(e.KeyID)))
+	b = append(b, e.KeyID...)
+
+	var flags byte
+	if !e.Attributes.SigningTime.IsZero() {
+		flags |= envelopeHasSigningTime
+	}
+	if e.Attributes.ContentType != "" {
+		flags |= envelopeHasContentType
+	}
+	b = append(b, flags)
+	if flags&envelopeHasSigningTime != 0 {
+		nanos := e.Attributes.SigningTime.UnixNano()
+		if !time.Unix(0, nanos).Equal(e.Attributes.SigningTime) {
+			return nil, fmt.Errorf("%w: signing time out of range", ErrSignatureEncoding)
+		}
+		b = binary.BigEndian.AppendUint64(b, uint64(nanos))
+	}
+	if flags&envelopeHasContentType != 0 {
+		b = binary.BigEndian.AppendUint16(b, uint16(len(e.Attributes.ContentType)))
+		b = append(b, e.Attributes.ContentType...)
+	}
+	return b, nil
+}
+
+// MarshalBinary encodes the envelope in the binary format.
+func (e *Envelope) MarshalBinary() ([]byte, error) {
+	if uint64(len(e.Signature)) > 0xffffffff {
+		return nil, fmt.Errorf("%w: signature longer than 2^32 - 1 bytes", ErrSignatureLength)
+	}
+	b, err := e.appendHeader([]byte{EnvelopeVersion})
+	if err != nil {
+		return nil, err
+	}
+	b = binary.BigEndian.AppendUint32(b, uint32(len(e.Signature)))
+	return append(b, e.Signature...), nil
+}
+
+// UnmarshalBinary decodes an envelope in the binary format. Unknown versions,
+// unknown attribute flags, truncated data and trailing data are rejected.
+func (e *Envelope) UnmarshalBinary(data []byte) error {
+	d := envelopeDecoder{data: data}
+	if version := d.bytes(1); d.err == nil && version[0] != EnvelopeVersion {
+		return fmt.Errorf("%w: unknown envelope version %d", ErrSignatureEncoding, version[0])
+	}
+	suiteType := string(d.bytes(int(d.uint(1))))
+	keyID := string(d.bytes(int(d.uint(2))))
+	var attributes EnvelopeAttributes
+	flags := d.uint(1)
+	if flags&^(envelopeHasSigningTime|envelopeHasContentType) != 0 {
+		return fmt.Errorf("%w: unknown envelope attributes", ErrSignatureEncoding)
+	}
+	if flags&envelopeHasSigningTime != 0 {
+		attributes.SigningTime = time.Unix(0, int64(d.uint(8))).UTC()
+	}
+	if flags&envelopeHasContentType != 0 {
+		attributes.ContentType = string(d.bytes(int(d.uint(2))))
+		if d.err == nil && attributes.ContentType == "" {
+			return fmt.Errorf("%w: empty content type", ErrSignatureEncoding)
+		}
+	}
+	signature := d.bytes(int(d.uint(4)))
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.data) != 0 {
+		return fmt.Errorf("%w: unexpected data after envelope", ErrSignatureEncoding)
+	}
+	if suiteType == "" {
+		return fmt.Errorf("%w: empty suite type", ErrSignatureEncoding)
+	}
+	*e = Envelope{
+		SuiteType:  suiteType,
+		KeyID:      keyID,
+		Signature:  append([]byte{}, signature...),
+		Attributes: attributes,
+	}
+	return nil
+}
+
+// Reads fields from the front of data, remembering the first error.
+type envelopeDecoder struct {
+	data []byte
+	err  error
+}
+
+func (d *envelopeDecoder) bytes(n int) []byte {
+	if d.err != nil {
+		return nil
+	}
+	if len(d.data) < n {
+		d.err = fmt.Errorf("%w: truncated envelope", ErrSignatureEncoding)
+		return nil
+	}
+	b := d.data[:n]
+	d.data = d.data[n:]
+	return b
+}
+
+// Reads a big endian unsigned integer of size 1, 2, 4 or 8 bytes.
+func (d *envelopeDecoder) uint(size int) uint64 {
+	var value uint64
+	for _, c := range d.bytes(size) {
+		value = value<<8 | uint64(c)
+	}
+	return value
+}
+
+type envelopeJSON struct {
+	SuiteType  string                  `json:"suite"`
+	KeyID      string                  `json:"keyId,omitempty"`
+	Signature  string                  `json:"signature"`
+	Attributes *envelopeAttributesJSON `json:"attributes,omitempty"`
+}
+
+type envelopeAttributesJSON struct {
+	SigningTime *time.Time `json:"signingTime,omitempty"`
+	ContentType string     `json:"contentType,omitempty"`
+}
+
+// MarshalJSON encodes the envelope as a JSON object with the signature in
+// hex, e.g.
+//
+//	{"suite": "ed25519", "keyId": "...", "signature": "...",
+//	 "attributes": {"signingTime": "2024-01-02T03:04:05Z", "contentType": "text/plain"}}
+func (e Envelope) MarshalJSON() ([]byte, error) {
+	encoded := envelopeJSON{
+		SuiteType: e.SuiteType,
+		KeyID:     e.KeyID,
+		Signature: ToHex(e.Signature),
+	}
+	if !e.Attributes.SigningTime.IsZero() || e.Attributes.ContentType != "" {
+		encoded.Attributes = &envelopeAttributesJSON{ContentType: e.Attributes.ContentType}
+		if !e.Attributes.SigningTime.IsZero() {
+			signingTime := e.Attributes.SigningTime.UTC()
+			encoded.Attributes.SigningTime = &signingTime
+		}
+	}
+	return json.Marshal(encoded)
+}
+
+// UnmarshalJSON decodes an envelope from the JSON encoding.
+func (e *Envelope) UnmarshalJSON(data []byte) error {
+	var decoded envelopeJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return err
+	}
+	if decoded.SuiteType == "" {
+		return fmt.Errorf("%w: empty suite type", ErrSignatureEncoding)
+	}
+	signature, err := FromHex(decoded.Signature)
+	if err != nil {
+		return fmt.Errorf("%w: signature is not hex", ErrSignatureEncoding)
+	}
+	envelope := Envelope{
+		SuiteType: decoded.SuiteType,
+		KeyID:     decoded.KeyID,
+		Signature: signature,
+	}
+	if decoded.Attributes != nil {
+		envelope.Attributes.ContentType = decoded.Attributes.ContentType
+		if decoded.Attributes.SigningTime != nil {
+			envelope.Attributes.SigningTime = decoded.Attributes.SigningTime.UTC()
+		}
+	}
+	*e = envelope
+	return nil
+}
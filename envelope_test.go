@@ -0,0 +1,220 @@
+package crypto
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var envelopeTestCases = []struct {
+	suiteType  string
+	privKeyHex string
+	pubKeyHex  string
+}{
+	{"ed25519", Ed25519PrivHex, Ed25519PubHex},
+	{"ecdsa_secp256k1_keccak256", Secp256k1PrivHex, Secp256k1PubHex},
+	{"ecdsa_P256_sha-256_rfc6979", P256PrivHex, P256PubHex},
+}
+
+func newEnvelopeResolver(keys map[string]string) PublicKeyResolver {
+	return func(suiteType string, keyID string) ([]byte, error) {
+		pubKeyHex, ok := keys[keyID]
+		if !ok {
+			return nil, errors.New("unknown key " + keyID)
+		}
+		return FromHex(pubKeyHex)
+	}
+}
+
+func TestSignEnvelope(t *testing.T) {
+	message := []byte("hello envelope")
+	attributes := EnvelopeAttributes{
+		SigningTime: time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("test", 3600)),
+		ContentType: "text/plain",
+	}
+	for _, tt := range envelopeTestCases {
+		t.Run(tt.suiteType, func(t *testing.T) {
+			privKey, _ := FromHex(tt.privKeyHex)
+			signer, _ := NewSignerForSuite(tt.suiteType, privKey)
+			resolve := newEnvelopeResolver(map[string]string{"key-1": tt.pubKeyHex})
+
+			envelope, err := SignEnvelope(signer, "key-1", message, attributes)
+			if err != nil {
+				t.Fatalf("Error signing: %s", err)
+			}
+			if envelope.SuiteType != tt.suiteType {
+				t.Errorf("Got suite %s, expected %s", envelope.SuiteType, tt.suiteType)
+			}
+			if envelope.Attributes.SigningTime.Location() != time.UTC {
+				t.Errorf("Expected signing time in UTC.")
+			}
+			if signer.Verify(message, envelope.Signature) {
+				t.Errorf("Expected signature to cover more than the message.")
+			}
+			ok, err := VerifyEnvelope(envelope, message, resolve)
+			if err != nil || !ok {
+				t.Errorf("Expected to verify envelope, got %v.", err)
+			}
+			ok, _ = VerifyEnvelope(envelope, []byte("other message"), resolve)
+			if ok {
+				t.Errorf("Expected envelope over another message to not verify.")
+			}
+
+			tampered := *envelope
+			tampered.Attributes.ContentType = "text/html"
+			if ok, _ := VerifyEnvelope(&tampered, message, resolve); ok {
+				t.Errorf("Expected envelope with changed content type to not verify.")
+			}
+			tampered = *envelope
+			tampered.Attributes.SigningTime = tampered.Attributes.SigningTime.Add(time.Nanosecond)
+			if ok, _ := VerifyEnvelope(&tampered, message, resolve); ok {
+				t.Errorf("Expected envelope with changed signing time to not verify.")
+			}
+			tampered = *envelope
+			tampered.KeyID = "key-2"
+			twoKeys := newEnvelopeResolver(map[string]string{"key-1": tt.pubKeyHex, "key-2": tt.pubKeyHex})
+			if ok, _ := VerifyEnvelope(&tampered, message, twoKeys); ok {
+				t.Errorf("Expected envelope with changed key ID to not verify.")
+			}
+		})
+	}
+}
+
+func TestVerifyEnvelopeErrors(t *testing.T) {
+	privKey, _ := FromHex(Ed25519PrivHex)
+	signer, _ := NewEd25519Signer(privKey)
+	envelope, _ := SignEnvelope(signer, "key-1", []byte{1}, EnvelopeAttributes{})
+
+	_, err := VerifyEnvelope(envelope, []byte{1}, newEnvelopeResolver(nil))
+	if err == nil {
+		t.Errorf("Expected resolver error.")
+	}
+
+	envelope.SuiteType = "unknown"
+	_, err = VerifyEnvelope(envelope, []byte{1}, newEnvelopeResolver(map[string]string{"key-1": Ed25519PubHex}))
+	if !errors.Is(err, ErrUnknownSuite) {
+		t.Errorf("Expected ErrUnknownSuite, got %v", err)
+	}
+
+	// The suite is signed, an ed25519ph verifier must not accept an ed25519
+	// envelope.
+	envelope.SuiteType = "ed25519ph"
+	ok, err := VerifyEnvelope(envelope, []byte{1}, newEnvelopeResolver(map[string]string{"key-1": Ed25519PubHex}))
+	if err != nil || ok {
+		t.Errorf("Expected envelope with changed suite to not verify, got %v.", err)
+	}
+}
+
+func TestEnvelopeBinary(t *testing.T) {
+	envelope := &Envelope{
+		SuiteType: "ed25519",
+		KeyID:     "k",
+		Signature: []byte{1, 2, 3},
+		Attributes: EnvelopeAttributes{
+			SigningTime: time.Unix(1, 2).UTC(),
+			ContentType: "a/b",
+		},
+	}
+	encoded, err := envelope.MarshalBinary()
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected, _ := FromHex("01" + "07" + ToHex([]byte("ed25519")) + "0001" + "6b" +
+		"03" + "000000003b9aca02" + "0003" + ToHex([]byte("a/b")) + "00000003" + "010203")
+	if !reflect.DeepEqual(encoded, expected) {
+		t.Errorf("Got %x, expected %x", encoded, expected)
+	}
+
+	decoded := &Envelope{}
+	if err := decoded.UnmarshalBinary(encoded); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(decoded, envelope) {
+		t.Errorf("Got %+v, expected %+v", decoded, envelope)
+	}
+
+	minimal := &Envelope{SuiteType: "ed25519", Signature: []byte{1}}
+	encoded, _ = minimal.MarshalBinary()
+	decoded = &Envelope{}
+	if err := decoded.UnmarshalBinary(encoded); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(decoded, minimal) {
+		t.Errorf("Got %+v, expected %+v", decoded, minimal)
+	}
+}
+
+func TestEnvelopeUnmarshalBinaryInvalid(t *testing.T) {
+	badEnvelopes := []struct {
+		name     string
+		envelope string
+	}{
+		{"empty", ""},
+		{"unknown_version", "02" + "01" + "61" + "0000" + "00" + "00000000"},
+		{"empty_suite", "01" + "00" + "0000" + "00" + "00000000"},
+		{"truncated_suite", "01" + "05" + "61"},
+		{"unknown_flags", "01" + "01" + "61" + "0000" + "04" + "00000000"},
+		{"empty_content_type", "01" + "01" + "61" + "0000" + "02" + "0000" + "00000000"},
+		{"truncated_time", "01" + "01" + "61" + "0000" + "01" + "0000"},
+		{"truncated_signature", "01" + "01" + "61" + "0000" + "00" + "00000002" + "01"},
+		{"trailing_data", "01" + "01" + "61" + "0000" + "00" + "00000000" + "00"},
+	}
+	for _, tt := range badEnvelopes {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := FromHex(tt.envelope)
+			err := (&Envelope{}).UnmarshalBinary(data)
+			if !errors.Is(err, ErrSignatureEncoding) {
+				t.Errorf("Expected ErrSignatureEncoding, got %v", err)
+			}
+		})
+	}
+}
+
+func TestEnvelopeJSON(t *testing.T) {
+	privKey, _ := FromHex(Ed25519PrivHex)
+	signer, _ := NewEd25519Signer(privKey)
+	envelope, _ := SignEnvelope(signer, "key-1", []byte{1}, EnvelopeAttributes{
+		SigningTime: time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC),
+		ContentType: "application/json",
+	})
+	encoded, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected := `{"suite":"ed25519","keyId":"key-1","signature":"` + ToHex(envelope.Signature) +
+		`","attributes":{"signingTime":"2024-01-02T03:04:05.000000006Z","contentType":"application/json"}}`
+	if string(encoded) != expected {
+		t.Errorf("Got %s, expected %s", encoded, expected)
+	}
+
+	decoded := &Envelope{}
+	if err := json.Unmarshal(encoded, decoded); err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if !reflect.DeepEqual(decoded, envelope) {
+		t.Errorf("Got %+v, expected %+v", decoded, envelope)
+	}
+	ok, err := VerifyEnvelope(decoded, []byte{1}, newEnvelopeResolver(map[string]string{"key-1": Ed25519PubHex}))
+	if err != nil || !ok {
+		t.Errorf("Expected to verify decoded envelope, got %v.", err)
+	}
+
+	encoded, _ = json.Marshal(&Envelope{SuiteType: "ed25519", Signature: []byte{1}})
+	if string(encoded) != `{"suite":"ed25519","signature":"01"}` {
+		t.Errorf("Got %s, expected attributes to be left out", encoded)
+	}
+}
+
+func TestEnvelopeUnmarshalJSONInvalid(t *testing.T) {
+	for _, data := range []string{
+		`{"signature":"01"}`,
+		`{"suite":"ed25519","signature":"0g"}`,
+		`{"suite":"ed25519","signature":"01","attributes":{"signingTime":"yesterday"}}`,
+	} {
+		if err := json.Unmarshal([]byte(data), &Envelope{}); err == nil {
+			t.Errorf("Expected error decoding %s", data)
+		}
+	}
+}
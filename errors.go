@@ -11,4 +11,57 @@ var (
 
 	// ErrSyntax occurs when decoding an invalid string
 	ErrSyntax = errors.New("invalid hex string")
+
+	// ErrUnknownSuite occurs when a suite type has not been registered
+	ErrUnknownSuite = errors.New("unknown suite type")
+
+	// ErrInvalidSuite occurs when registering a suite with missing information
+	ErrInvalidSuite = errors.New("invalid suite")
+
+	// ErrSuiteRegistered occurs when registering a suite type a second time
+	ErrSuiteRegistered = errors.New("suite type already registered")
+
+	// ErrSuiteCannotRecover occurs when recovering a public key with a suite that
+	// does not support it
+	ErrSuiteCannotRecover = errors.New("suite does not support public key recovery")
+
+	// ErrSignatureLength occurs when a signature is not the length the suite expects
+	ErrSignatureLength = errors.New("invalid signature length")
+
+	// ErrSignatureEncoding occurs when a signature can not be decoded
+	ErrSignatureEncoding = errors.New("invalid signature encoding")
+
+	// ErrSignatureMismatch occurs when a well formed signature is not a valid
+	// signature of the message by the verifier's key
+	ErrSignatureMismatch = errors.New("signature does not match")
+
+	// ErrInvalidPublicKey occurs when a public key can not be decoded or is not
+	// a valid point of the suite's curve
+	ErrInvalidPublicKey = errors.New("invalid public key")
+
+	// ErrSuiteCannotSign occurs when requesting a signer from a verify only suite
+	ErrSuiteCannotSign = errors.New("suite does not support signing")
+
+	// ErrSuiteCannotPrehash occurs when hashing and signing separately with a
+	// suite that signs messages directly, e.g. pure Ed25519
+	ErrSuiteCannotPrehash = errors.New("suite does not sign a hash of the message")
+
+	// ErrDigestLength occurs when a digest is not the length of the suite's hash
+	ErrDigestLength = errors.New("invalid digest length")
+
+	// ErrUnexpectedKeyType occurs when parsed key data holds a different kind of
+	// key than the one requested, e.g. an ECDSA key where an RSA key was expected
+	ErrUnexpectedKeyType = errors.New("unexpected key type")
+
+	// ErrUnexpectedSuite occurs when a signer or verifier of one suite is used
+	// where another is required, e.g. signing Ethereum messages with ed25519
+	ErrUnexpectedSuite = errors.New("unexpected suite type")
+
+	// ErrUnexpectedHash occurs when a digest was computed with a different hash
+	// function than the suite uses
+	ErrUnexpectedHash = errors.New("unexpected hash function")
+
+	// ErrTypedData occurs when EIP-712 typed data is malformed or a value does
+	// not match its declared type
+	ErrTypedData = errors.New("invalid EIP-712 typed data")
 )
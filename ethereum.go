@@ -0,0 +1,181 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+)
+
+// EthereumAddressLength Length of an Ethereum account address
+const EthereumAddressLength = 20
+
+func init() {
+	mustRegisterECDSASuite(
+		"ecdsa_secp256k1_keccak256_address",
+		nil,
+		NewSecp256k1Keccak256AddressVerifier,
+	)
+}
+
+// EthereumAddress derives the 20 byte Ethereum address of a 64 byte secp256k1
+// public key, the last 20 bytes of the Keccak256 hash of the key.
+func EthereumAddress(pubKey []byte) ([]byte, error) {
+	if len(pubKey) != Secp256k1PublicKeyLength {
+		return nil, errors.New("key should be 64 bytes got " + fmt.Sprint(len(pubKey)))
+	}
+	hash := (&Keccak256Hasher{}).Hash(pubKey)
+	return hash[len(hash)-EthereumAddressLength:], nil
+}
+
+// EthereumAddressToHex encodes an address as 0x prefixed hex using the mixed
+// case checksum described in EIP-55.
+func EthereumAddressToHex(address []byte) (string, error) {
+	if len(address) != EthereumAddressLength {
+		return "", errors.New("address should be 20 bytes got " + fmt.Sprint(len(address)))
+	}
+	lower := ToHex(address)
+	hash := (&Keccak256Hasher{}).Hash([]byte(lower))
+	checksummed := []byte(lower)
+	for i, c := range checksummed {
+		if c < 'a' {
+			continue
+		}
+		// Each hex character is upper cased when the matching nibble of the
+		// hash of the lower case address is 8 or more.
+		nibble := hash[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0xf >= 8 {
+			checksummed[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(checksummed), nil
+}
+
+// EthereumAddressFromHex decodes a hex address with or without the 0x prefix.
+// Addresses in mixed case must have a valid EIP-55 checksum, all lower or all
+// upper case addresses are accepted without one.
+func EthereumAddressFromHex(hexAddress string) ([]byte, error) {
+	trimmed := strings.TrimPrefix(strings.TrimPrefix(hexAddress, "0x"), "0X")
+	address, err := FromHex(trimmed)
+	if err != nil {
+		return nil, ErrSyntax
+	}
+	if len(address) != EthereumAddressLength {
+		return nil, errors.New("address should be 20 bytes got " + fmt.Sprint(len(address)))
+	}
+	if trimmed == strings.ToLower(trimmed) || trimmed == strings.ToUpper(trimmed) {
+		return address, nil
+	}
+	checksummed, _ := EthereumAddressToHex(address)
+	if checksummed[2:] != trimmed {
+		return nil, errors.New("invalid EIP-55 address checksum")
+	}
+	return address, nil
+}
+
+// NewSecp256k1Keccak256AddressVerifier constructor for a Verifier that only
+// knows the Ethereum address of the signer. The public key is recovered from
+// each signature and its address compared against the expected one. It
+// verifies the signatures of ecdsa_secp256k1_keccak256 signers, its suite
+// type is ecdsa_secp256k1_keccak256_address, which NewVerifierForSuite builds
+// from the 20 byte address.
+func NewSecp256k1Keccak256AddressVerifier(address []byte) (Verifier, error) {
+	if len(address) != EthereumAddressLength {
+		return nil, errors.New("address should be 20 bytes got " + fmt.Sprint(len(address)))
+	}
+	return &ethereumAddressVerifier{
+		address:   address,
+		suiteType: "ecdsa_secp256k1_keccak256_address",
+	}, nil
+}
+
+type ethereumAddressVerifier struct {
+	address   []byte
+	suiteType string
+}
+
+func (s *ethereumAddressVerifier) SuiteType() string {
+	return s.suiteType
+}
+
+func (s *ethereumAddressVerifier) Verify(toVerify []byte, signature []byte) bool {
+	return s.VerifyErr(toVerify, signature) == nil
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *ethereumAddressVerifier) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifyDigest((&Keccak256Hasher{}).Hash(toVerify), signature)
+}
+
+func (s *ethereumAddressVerifier) verifyDigest(digest []byte, signature []byte) error {
+	key, err := recoverSecp256k1(digest, signature)
+	if err != nil {
+		return err
+	}
+	address, err := EthereumAddress(key.SerializeUncompressed()[1:])
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(address, s.address) {
+		return fmt.Errorf("%w: signed by another address", ErrSignatureMismatch)
+	}
+	return nil
+}
+
+func (s *ethereumAddressVerifier) newHash() hash.Hash {
+	return (&Keccak256Hasher{}).newHash()
+}
+
+// VerifyDigest verifies a signature over a digest, see DigestVerifier.
+func (s *ethereumAddressVerifier) VerifyDigest(digest []byte, signature []byte) bool {
+	return verifyCheckedDigest(s, digest, signature)
+}
+
+// EthereumPersonalMessageHash returns the EIP-191 version 0x45 digest of
+// message, as computed by the personal_sign and eth_sign JSON-RPC methods:
+// keccak256("\x19Ethereum Signed Message:\n" || len(message) || message).
+func EthereumPersonalMessageHash(message []byte) []byte {
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))
+	return (&Keccak256Hasher{}).Hash([]byte(prefix), message)
+}
+
+// SignEthereumPersonalMessage signs message as personal_sign does, with an
+// ecdsa_secp256k1_keccak256 signer. The signature is R || S || V with V 0 or
+// 1, wallets return V + 27.
+func SignEthereumPersonalMessage(signer Signer, message []byte) ([]byte, error) {
+	return signEthereumDigest(signer, EthereumPersonalMessageHash(message))
+}
+
+// VerifyEthereumPersonalMessage verifies a personal_sign signature over
+// message. The verifier may be an ecdsa_secp256k1_keccak256 verifier or an
+// address verifier, V may be either 0 or 1 or the 27 or 28 used by wallets.
+func VerifyEthereumPersonalMessage(verifier Verifier, message []byte, signature []byte) bool {
+	return verifyEthereumDigest(verifier, EthereumPersonalMessageHash(message), signature)
+}
+
+func signEthereumDigest(signer Signer, digest []byte) ([]byte, error) {
+	digestSigner, ok := signer.(DigestSigner)
+	if !ok || signer.SuiteType() != "ecdsa_secp256k1_keccak256" {
+		return nil, fmt.Errorf(
+			"%w: expected ecdsa_secp256k1_keccak256, got %s",
+			ErrUnexpectedSuite, signer.SuiteType())
+	}
+	return digestSigner.SignDigest(digest)
+}
+
+func verifyEthereumDigest(verifier Verifier, digest []byte, signature []byte) bool {
+	digestVerifier, ok := verifier.(DigestVerifier)
+	if !ok || (verifier.SuiteType() != "ecdsa_secp256k1_keccak256" &&
+		verifier.SuiteType() != "ecdsa_secp256k1_keccak256_address") {
+		return false
+	}
+	if len(signature) == Secp256k1SignatureLength && signature[64] >= 27 {
+		signature = append([]byte{}, signature...)
+		signature[64] -= 27
+	}
+	return digestVerifier.VerifyDigest(digest, signature)
+}
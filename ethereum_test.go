@@ -0,0 +1,214 @@
+package crypto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Address of Secp256k1PubHex as reported by go-ethereum.
+const Secp256k1AddressHex = "0x970E8128AB834E8EAC17Ab8E3812F010678CF791"
+
+// Test vectors from EIP-55, including addresses whose checksum is all upper
+// or all lower case.
+var eip55TestCases = []string{
+	"0x52908400098527886E0F7030069857D2E4169EE7",
+	"0x8617E340B3D01FA5F11F306F4090FD50E238070D",
+	"0xde709f2102306220921060314715629080e2fb77",
+	"0x27b1fdb04752bbc536007a920d24acb045561c26",
+	"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+	"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+	"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+	"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+}
+
+func TestEthereumAddressToHex(t *testing.T) {
+	for _, tt := range eip55TestCases {
+		t.Run(tt, func(t *testing.T) {
+			address, err := EthereumAddressFromHex(tt)
+			if err != nil {
+				t.Fatalf("Unexpected error %s", err)
+			}
+			result, _ := EthereumAddressToHex(address)
+			if result != tt {
+				t.Errorf("Got %s, expected %s", result, tt)
+			}
+		})
+	}
+}
+
+func TestEthereumAddressFromHexBadChecksum(t *testing.T) {
+	_, err := EthereumAddressFromHex("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	if err == nil {
+		t.Errorf("Expected checksum error.")
+	}
+	_, err = EthereumAddressFromHex("0x5aAeb6")
+	if err == nil {
+		t.Errorf("Expected length error.")
+	}
+	_, err = EthereumAddressFromHex("0xzz")
+	if !errors.Is(err, ErrSyntax) {
+		t.Errorf("Expected ErrSyntax, got %v", err)
+	}
+}
+
+func TestEthereumAddress(t *testing.T) {
+	pubKey, _ := FromHex(Secp256k1PubHex)
+	address, err := EthereumAddress(pubKey)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	result, _ := EthereumAddressToHex(address)
+	if result != Secp256k1AddressHex {
+		t.Errorf("Got %s, expected %s", result, Secp256k1AddressHex)
+	}
+
+	if _, err := EthereumAddress(pubKey[1:]); err == nil {
+		t.Errorf("Expected key length error.")
+	}
+}
+
+func TestRecoverPublicKey(t *testing.T) {
+	expected, _ := FromHex(Secp256k1PubHex)
+	for _, tt := range secp256k1TestCases {
+		t.Run(ToHex(tt.message), func(t *testing.T) {
+			signature, _ := FromHex(tt.signature)
+			pubKey, err := RecoverPublicKey("ecdsa_secp256k1_keccak256", tt.message, signature)
+			if err != nil {
+				t.Fatalf("Unexpected error %s", err)
+			}
+			if !reflect.DeepEqual(pubKey, expected) {
+				t.Errorf("Expected %x, pubkey was %x.", expected, pubKey)
+			}
+
+			other, err := RecoverPublicKey("ecdsa_secp256k1_keccak256", append(tt.message, 1), signature)
+			if err == nil && reflect.DeepEqual(other, expected) {
+				t.Errorf("Recovered the signer's key for another message.")
+			}
+		})
+	}
+}
+
+func TestRecoverPublicKeyErrors(t *testing.T) {
+	signature, _ := FromHex(secp256k1TestCases[0].signature)
+	_, err := RecoverPublicKey("ed25519", nil, signature)
+	if !errors.Is(err, ErrSuiteCannotRecover) {
+		t.Errorf("Expected ErrSuiteCannotRecover, got %v", err)
+	}
+	_, err = RecoverPublicKey("ecdsa_secp256k1_keccak256", nil, signature[:64])
+	if !errors.Is(err, ErrSignatureLength) {
+		t.Errorf("Expected ErrSignatureLength, got %v", err)
+	}
+	_, err = RecoverPublicKey("ecdsa_secp256k1_keccak256", nil, highSSecp256k1Signature(signature))
+	if !errors.Is(err, ErrSignatureEncoding) {
+		t.Errorf("Expected ErrSignatureEncoding, got %v", err)
+	}
+}
+
+func TestSecp256k1AddressVerifier(t *testing.T) {
+	address, _ := EthereumAddressFromHex(Secp256k1AddressHex)
+	verifier, err := NewSecp256k1Keccak256AddressVerifier(address)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if verifier.SuiteType() != "ecdsa_secp256k1_keccak256_address" {
+		t.Errorf("Expected suite type ecdsa_secp256k1_keccak256_address got %s", verifier.SuiteType())
+	}
+	// The suite type and address build the same verifier through the registry.
+	registryVerifier, err := NewVerifierForSuite(verifier.SuiteType(), address)
+	if err != nil {
+		t.Fatalf("Unexpected error %s", err)
+	}
+	if registryVerifier.SuiteType() != verifier.SuiteType() {
+		t.Errorf("Got %s, expected %s", registryVerifier.SuiteType(), verifier.SuiteType())
+	}
+	if _, err := NewSignerForSuite(verifier.SuiteType(), nil); !errors.Is(err, ErrSuiteCannotSign) {
+		t.Errorf("Got %v, expected %v", err, ErrSuiteCannotSign)
+	}
+	for _, tt := range secp256k1TestCases {
+		signature, _ := FromHex(tt.signature)
+		if !verifier.Verify(tt.message, signature) || !registryVerifier.Verify(tt.message, signature) {
+			t.Errorf("Expected to verify signature for %x.", tt.message)
+		}
+		if verifier.Verify(append(tt.message, 1), signature) {
+			t.Errorf("Expected signature over another message to not verify.")
+		}
+		err := verifier.(ErrorVerifier).VerifyErr(append(tt.message, 1), signature)
+		if !errors.Is(err, ErrSignatureMismatch) {
+			t.Errorf("Got %v, expected %v", err, ErrSignatureMismatch)
+		}
+	}
+
+	_, err = NewSecp256k1Keccak256AddressVerifier(address[1:])
+	if err == nil {
+		t.Errorf("Expected address length error.")
+	}
+}
+
+// Computed with go-ethereum's accounts.TextHash, signatures by the
+// Secp256k1PrivHex key.
+var personalMessageTestCases = []struct {
+	message   string
+	digest    string
+	signature string
+}{
+	{
+		"",
+		"5f35dce98ba4fba25530a026ed80b2cecdaa31091ba4958b99b52ea1d068adad",
+		"c5b23b9fd9c97515ae3154d77d0fd36351d01c79d04ba251740ac167a516d3d34a6146d43180336ce0c24080401cbcff508ed73ae3f04a096f7a30653f63f77a00",
+	},
+	{
+		"hello world",
+		"d9eba16ed0ecae432b71fe008c98cc872bb4cc214d3220a36f365326cf807d68",
+		"f7612f55807611eb00b4ff2d4831f9b44c6020c81dbe43a31ddf3c5573b45f087f44110b5b8a627e41cd332afd4f2b0cb9e87047c1f46e4903b96f7f1e3112ea00",
+	},
+}
+
+func TestEthereumPersonalMessage(t *testing.T) {
+	privKey, _ := FromHex(Secp256k1PrivHex)
+	pubKey, _ := FromHex(Secp256k1PubHex)
+	signer, _ := NewSecp256k1Keccak256Signer(privKey)
+	verifier, _ := NewSecp256k1Keccak256Verifier(pubKey)
+
+	for _, tt := range personalMessageTestCases {
+		t.Run(tt.message, func(t *testing.T) {
+			digest := EthereumPersonalMessageHash([]byte(tt.message))
+			if ToHex(digest) != tt.digest {
+				t.Errorf("Got %x, expected %s", digest, tt.digest)
+			}
+			signature, err := SignEthereumPersonalMessage(signer, []byte(tt.message))
+			if err != nil {
+				t.Fatalf("Error signing: %s", err)
+			}
+			if ToHex(signature) != tt.signature {
+				t.Errorf("Got %x, expected %s", signature, tt.signature)
+			}
+			if !VerifyEthereumPersonalMessage(verifier, []byte(tt.message), signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+			if VerifyEthereumPersonalMessage(verifier, []byte(tt.message+"!"), signature) {
+				t.Errorf("Expected signature over another message to not verify.")
+			}
+			// personal_sign signatures are not signatures over the message.
+			if verifier.Verify([]byte(tt.message), signature) {
+				t.Errorf("Expected plain verification to fail.")
+			}
+		})
+	}
+}
+
+func TestEthereumPersonalMessageWrongSuite(t *testing.T) {
+	privKey, _ := FromHex(P256PrivHex)
+	pubKey, _ := FromHex(P256PubHex)
+	signer, _ := NewP256Sha_256RFC6979Signer(privKey)
+	verifier, _ := NewP256Sha_256Verifier(pubKey)
+
+	_, err := SignEthereumPersonalMessage(signer, []byte{1})
+	if !errors.Is(err, ErrUnexpectedSuite) {
+		t.Errorf("Expected ErrUnexpectedSuite, got %v", err)
+	}
+	signature, _ := signer.(DigestSigner).SignDigest(EthereumPersonalMessageHash([]byte{1}))
+	if VerifyEthereumPersonalMessage(verifier, []byte{1}, signature) {
+		t.Errorf("Expected P256 verifier to be rejected.")
+	}
+}
@@ -1,3 +1,16 @@
 module github.com/kochavalabs/crypto
 
-require golang.org/x/crypto v0.0.0-20190219172222-a4c6cb3142f2
+go 1.22.0
+
+require (
+	filippo.io/edwards25519 v1.1.0
+	github.com/cloudflare/circl v1.6.1
+	github.com/decred/dcrd/dcrec/secp256k1/v4 v4.4.0
+	github.com/fxamacker/cbor/v2 v2.9.2
+	golang.org/x/crypto v0.31.0
+)
+
+require (
+	github.com/x448/float16 v0.8.4 // indirect
+	golang.org/x/sys v0.28.0 // indirect
+)
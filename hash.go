@@ -24,6 +24,22 @@ func hashHex(hashFunc hash.Hash, input ...[]byte) string {
 	return ToHex(hashBytes(hashFunc, input...))
 }
 
+// streamHasher is implemented by the hashers in this package that can also
+// hash a message incrementally, see SignReader.
+type streamHasher interface {
+	newHash() hash.Hash
+}
+
+// Returns a new hash.Hash computing the same digest as hasher, or false if the
+// hasher can not be used incrementally.
+func newStreamHash(hasher Hasher) (hash.Hash, bool) {
+	streaming, ok := hasher.(streamHasher)
+	if !ok {
+		return nil, false
+	}
+	return streaming.newHash(), true
+}
+
 type MockHasher struct {
 	hex   string
 	bytes []byte
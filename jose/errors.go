@@ -0,0 +1,39 @@
+package jose
+
+import "errors"
+
+var (
+	// ErrMalformed occurs when a JWS or JWT can not be decoded
+	ErrMalformed = errors.New("malformed JWS")
+
+	// ErrAlgorithm occurs when a suite has no JWS algorithm, or when a JWS
+	// names a different algorithm than the one the verifier implements
+	ErrAlgorithm = errors.New("unexpected JWS algorithm")
+
+	// ErrCritical occurs when a JWS has critical header parameters, none are
+	// supported
+	ErrCritical = errors.New("unsupported critical header parameter")
+
+	// ErrSignature occurs when a JWS signature does not verify
+	ErrSignature = errors.New("JWS signature verification failed")
+
+	// ErrType occurs when a JWT has a typ header other than JWT
+	ErrType = errors.New("unexpected token type")
+
+	// ErrExpired occurs when the exp claim of a JWT is in the past
+	ErrExpired = errors.New("token is expired")
+
+	// ErrNotYetValid occurs when the nbf claim of a JWT is in the future
+	ErrNotYetValid = errors.New("token is not valid yet")
+
+	// ErrIssuer occurs when the iss claim of a JWT is not the expected issuer
+	ErrIssuer = errors.New("unexpected token issuer")
+
+	// ErrAudience occurs when the aud claim of a JWT does not contain the
+	// expected audience
+	ErrAudience = errors.New("unexpected token audience")
+
+	// ErrMissingClaim occurs when a claim required by the validation options
+	// is missing
+	ErrMissingClaim = errors.New("missing required claim")
+)
@@ -0,0 +1,327 @@
+// Package jose creates and verifies JSON Web Signatures (RFC 7515) and JSON
+// Web Tokens (RFC 7519) with the Signers and Verifiers of the crypto package.
+//
+// The JWS algorithm is taken from the suite type of the signer or verifier,
+// never chosen by the token. A verifier only accepts tokens whose alg header
+// names its own algorithm, so a token can not switch a verifier to another
+// algorithm, to "none" or to an HMAC keyed with the public key.
+package jose
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kochavalabs/crypto"
+)
+
+// JWS algorithm names from RFC 7518, RFC 8037 and RFC 8812.
+const (
+	ES256  = "ES256"
+	ES384  = "ES384"
+	ES512  = "ES512"
+	ES256K = "ES256K"
+	EdDSA  = "EdDSA"
+	PS256  = "PS256"
+	PS384  = "PS384"
+	RS256  = "RS256"
+)
+
+// Suites whose signatures are valid JWS signatures of an algorithm. Signer
+// suite types are listed along with the verifier suite type they share a
+// signature format with.
+var suiteAlgorithms = map[string]string{
+	"ecdsa_P256_sha-256":         ES256,
+	"ecdsa_P256_sha-256_rfc6979": ES256,
+	"ecdsa_P256_sha-256_indet":   ES256,
+	"ecdsa_P384_sha-384":         ES384,
+	"ecdsa_P384_sha-384_rfc6979": ES384,
+	"ecdsa_P384_sha-384_indet":   ES384,
+	"ecdsa_P521_sha-512":         ES512,
+	"ecdsa_P521_sha-512_rfc6979": ES512,
+	"ecdsa_P521_sha-512_indet":   ES512,
+	"ecdsa_secp256k1_sha-256":    ES256K,
+	"ed25519":                    EdDSA,
+	"ed448":                      EdDSA,
+	"rsa_pss_sha-256":            PS256,
+	"rsa_pss_sha-384":            PS384,
+	"rsa_pkcs1v15_sha-256":       RS256,
+}
+
+// AlgorithmForSuite returns the JWS alg of a suite type, e.g. ES256 for
+// ecdsa_P256_sha-256_rfc6979.
+func AlgorithmForSuite(suiteType string) (string, error) {
+	alg, ok := suiteAlgorithms[suiteType]
+	if !ok {
+		return "", fmt.Errorf("%w: suite %s has no JWS algorithm", ErrAlgorithm, suiteType)
+	}
+	return alg, nil
+}
+
+// Header is the JOSE header of a JWS. Algorithm is set from the signer's
+// suite when signing.
+type Header struct {
+	Algorithm   string `json:"alg"`
+	KeyID       string `json:"kid,omitempty"`
+	Type        string `json:"typ,omitempty"`
+	ContentType string `json:"cty,omitempty"`
+}
+
+// JSONSigner is one of the signers of a JWS JSON serialization and the header
+// protected by its signature.
+type JSONSigner struct {
+	Signer crypto.Signer
+	Header Header
+}
+
+var encoding = base64.RawURLEncoding
+
+func decodeSegment(segment string) ([]byte, error) {
+	decoded, err := encoding.Strict().DecodeString(segment)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid base64url", ErrMalformed)
+	}
+	return decoded, nil
+}
+
+// SignCompact signs payload and returns the JWS compact serialization,
+// header.payload.signature.
+func SignCompact(signer crypto.Signer, header Header, payload []byte) (string, error) {
+	protected, err := encodeProtectedHeader(signer, header)
+	if err != nil {
+		return "", err
+	}
+	signingInput := protected + "." + encoding.EncodeToString(payload)
+	signature, err := signer.Sign([]byte(signingInput))
+	if err != nil {
+		return "", err
+	}
+	return signingInput + "." + encoding.EncodeToString(signature), nil
+}
+
+// VerifyCompact verifies a JWS compact serialization, returning its payload
+// and header.
+func VerifyCompact(verifier crypto.Verifier, token string) ([]byte, *Header, error) {
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return nil, nil, fmt.Errorf("%w: expected 3 segments, got %d", ErrMalformed, len(segments))
+	}
+	header, _, err := decodeProtectedHeader(segments[0])
+	if err != nil {
+		return nil, nil, err
+	}
+	payload, err := decodeSegment(segments[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	signature, err := decodeSegment(segments[2])
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := verifySignature(verifier, header, segments[0]+"."+segments[1], signature); err != nil {
+		return nil, nil, err
+	}
+	return payload, header, nil
+}
+
+type jwsSignatureJSON struct {
+	Protected string                     `json:"protected,omitempty"`
+	Header    map[string]json.RawMessage `json:"header,omitempty"`
+	Signature string                     `json:"signature,omitempty"`
+}
+
+type jwsJSON struct {
+	Payload    *string            `json:"payload"`
+	Signatures []jwsSignatureJSON `json:"signatures,omitempty"`
+	jwsSignatureJSON
+}
+
+// SignJSON signs payload with each signer and returns the JWS JSON
+// serialization, flattened when there is a single signer. All header
+// parameters are protected.
+func SignJSON(payload []byte, signers ...JSONSigner) ([]byte, error) {
+	if len(signers) == 0 {
+		return nil, errors.New("at least one signer is required")
+	}
+	encodedPayload := encoding.EncodeToString(payload)
+	signatures := make([]jwsSignatureJSON, 0, len(signers))
+	for _, s := range signers {
+		protected, err := encodeProtectedHeader(s.Signer, s.Header)
+		if err != nil {
+			return nil, err
+		}
+		signature, err := s.Signer.Sign([]byte(protected + "." + encodedPayload))
+		if err != nil {
+			return nil, err
+		}
+		signatures = append(signatures, jwsSignatureJSON{
+			Protected: protected,
+			Signature: encoding.EncodeToString(signature),
+		})
+	}
+	encoded := jwsJSON{Payload: &encodedPayload}
+	if len(signatures) == 1 {
+		encoded.jwsSignatureJSON = signatures[0]
+	} else {
+		encoded.Signatures = signatures
+	}
+	return json.Marshal(encoded)
+}
+
+// VerifyJSON verifies a flattened or general JWS JSON serialization. It
+// succeeds when one of the signatures with the verifier's algorithm verifies,
+// returning the payload and the header of that signature, with unprotected
+// header parameters merged in. The alg header parameter must be protected.
+func VerifyJSON(verifier crypto.Verifier, data []byte) ([]byte, *Header, error) {
+	var decoded jwsJSON
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+	}
+	if decoded.Payload == nil {
+		return nil, nil, fmt.Errorf("%w: missing payload", ErrMalformed)
+	}
+	payload, err := decodeSegment(*decoded.Payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	flattened := decoded.jwsSignatureJSON
+	signatures := decoded.Signatures
+	isFlattened := flattened.Protected != "" || flattened.Header != nil || flattened.Signature != ""
+	if isFlattened == (len(signatures) > 0) {
+		return nil, nil, fmt.Errorf("%w: expected either signatures or a flattened signature", ErrMalformed)
+	}
+	if isFlattened {
+		signatures = []jwsSignatureJSON{flattened}
+	}
+
+	// Report an algorithm mismatch only if no signature had the right one.
+	verifyErr := error(nil)
+	for _, s := range signatures {
+		header, err := decodeJSONSignatureHeader(s)
+		if err != nil {
+			return nil, nil, err
+		}
+		signature, err := decodeSegment(s.Signature)
+		if err != nil {
+			return nil, nil, err
+		}
+		err = verifySignature(verifier, header, s.Protected+"."+*decoded.Payload, signature)
+		if err == nil {
+			return payload, header, nil
+		}
+		if verifyErr == nil || errors.Is(err, ErrSignature) {
+			verifyErr = err
+		}
+	}
+	return nil, nil, verifyErr
+}
+
+// Decodes the protected header and merges in the unprotected one, which must
+// not repeat protected parameters or set alg or crit.
+func decodeJSONSignatureHeader(s jwsSignatureJSON) (*Header, error) {
+	if s.Protected == "" {
+		return nil, fmt.Errorf("%w: missing protected header", ErrMalformed)
+	}
+	header, protectedParams, err := decodeProtectedHeader(s.Protected)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.Header) == 0 {
+		return header, nil
+	}
+	for name := range s.Header {
+		if _, ok := protectedParams[name]; ok {
+			return nil, fmt.Errorf("%w: header parameter %s is both protected and unprotected", ErrMalformed, name)
+		}
+		if name == "alg" || name == "crit" {
+			return nil, fmt.Errorf("%w: header parameter %s must be protected", ErrMalformed, name)
+		}
+	}
+	params := make(map[string]json.RawMessage, len(protectedParams)+len(s.Header))
+	for name, value := range protectedParams {
+		params[name] = value
+	}
+	for name, value := range s.Header {
+		params[name] = value
+	}
+	return headerFromParams(params)
+}
+
+func encodeProtectedHeader(signer crypto.Signer, header Header) (string, error) {
+	alg, err := AlgorithmForSuite(signer.SuiteType())
+	if err != nil {
+		return "", err
+	}
+	if header.Algorithm != "" && header.Algorithm != alg {
+		return "", fmt.Errorf("%w: header has %s, signer is %s", ErrAlgorithm, header.Algorithm, alg)
+	}
+	header.Algorithm = alg
+	encoded, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	return encoding.EncodeToString(encoded), nil
+}
+
+// Decodes a base64url protected header, also returning its raw parameters.
+// Headers without an alg or with crit are rejected.
+func decodeProtectedHeader(segment string) (*Header, map[string]json.RawMessage, error) {
+	data, err := decodeSegment(segment)
+	if err != nil {
+		return nil, nil, err
+	}
+	var params map[string]json.RawMessage
+	if err := json.Unmarshal(data, &params); err != nil || params == nil {
+		return nil, nil, fmt.Errorf("%w: header is not a JSON object", ErrMalformed)
+	}
+	if _, ok := params["crit"]; ok {
+		return nil, nil, ErrCritical
+	}
+	header, err := headerFromParams(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header.Algorithm == "" {
+		return nil, nil, fmt.Errorf("%w: missing alg header", ErrMalformed)
+	}
+	return header, params, nil
+}
+
+// Builds a Header from raw header parameters. Parameter names are case
+// sensitive, unlike the field names json.Unmarshal matches, so e.g. "ALG" is
+// an unknown parameter and never sets Algorithm.
+func headerFromParams(params map[string]json.RawMessage) (*Header, error) {
+	header := &Header{}
+	fields := map[string]*string{
+		"alg": &header.Algorithm,
+		"kid": &header.KeyID,
+		"typ": &header.Type,
+		"cty": &header.ContentType,
+	}
+	for name, field := range fields {
+		value, ok := params[name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(value, field); err != nil {
+			return nil, fmt.Errorf("%w: header parameter %s: %s", ErrMalformed, name, err)
+		}
+	}
+	return header, nil
+}
+
+func verifySignature(verifier crypto.Verifier, header *Header, signingInput string, signature []byte) error {
+	alg, err := AlgorithmForSuite(verifier.SuiteType())
+	if err != nil {
+		return err
+	}
+	if header.Algorithm != alg {
+		return fmt.Errorf("%w: expected %s, got %q", ErrAlgorithm, alg, header.Algorithm)
+	}
+	if !verifier.Verify([]byte(signingInput), signature) {
+		return ErrSignature
+	}
+	return nil
+}
@@ -0,0 +1,342 @@
+package jose
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/kochavalabs/crypto"
+)
+
+// The RFC 8032 test 1 key used in RFC 8037 appendix A.
+const ed25519Seed = "9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60"
+
+const p256PrivHex = "25590b07bb236b0cdc4052550093684efe4e8123291c11095e1360203c0b1a63"
+const p256PubHex = "0e609d4eea6ecac33fd083bf108e90db5a31fbf9239bc5cc19a8a6dd10b61050c746f61b03ab399bcc5d18bd33953b4e73a4fdf7529f58747304a32c4814d24e"
+
+const secp256k1PrivHex = "289c2857d4598e37fb9647507e47a309d6133539bf21a8b9cb6df88fd5232032"
+
+type testKey struct {
+	suiteType string
+	priv      []byte
+	pub       []byte
+}
+
+func testKeys(t *testing.T) map[string]testKey {
+	edPub, edPriv, _ := crypto.Ed25519KeyPairFromSeed(mustHex(ed25519Seed))
+	k1Priv := mustHex(secp256k1PrivHex)
+	k1Pub, _ := crypto.Secp256k1PublicKeyFromPrivate(k1Priv)
+	p384Priv, _, _ := crypto.GenerateKeyPairP384()
+	rsaPriv, rsaPub, _ := crypto.GenerateRSAKeyPair(crypto.RSAMinimumKeyBits)
+	rsaPrivDER, _ := crypto.MarshalRSAPrivateKeyPKCS1(rsaPriv)
+	rsaPubDER, _ := crypto.MarshalRSAPublicKeyPKIX(rsaPub)
+	return map[string]testKey{
+		EdDSA:  {"ed25519", edPriv, edPub},
+		ES256:  {"ecdsa_P256_sha-256_rfc6979", mustHex(p256PrivHex), mustHex(p256PubHex)},
+		ES256K: {"ecdsa_secp256k1_sha-256", k1Priv, k1Pub},
+		ES384: {
+			"ecdsa_P384_sha-384_rfc6979",
+			p384Priv.D.FillBytes(make([]byte, 48)),
+			append(p384Priv.X.FillBytes(make([]byte, 48)), p384Priv.Y.FillBytes(make([]byte, 48))...),
+		},
+		PS256: {"rsa_pss_sha-256", rsaPrivDER, rsaPubDER},
+	}
+}
+
+func mustHex(s string) []byte {
+	decoded, err := crypto.FromHex(s)
+	if err != nil {
+		panic(err)
+	}
+	return decoded
+}
+
+func newTestPair(t *testing.T, key testKey) (crypto.Signer, crypto.Verifier) {
+	signer, err := crypto.NewSignerForSuite(key.suiteType, key.priv)
+	if err != nil {
+		t.Fatalf("Error creating the signer: %s", err)
+	}
+	verifier, err := crypto.NewVerifierForSuite(signer.SuiteType(), key.pub)
+	if err != nil {
+		t.Fatalf("Error creating the verifier: %s", err)
+	}
+	return signer, verifier
+}
+
+func TestAlgorithmForSuite(t *testing.T) {
+	for suiteType, expected := range map[string]string{
+		"ecdsa_P256_sha-256":         ES256,
+		"ecdsa_P256_sha-256_rfc6979": ES256,
+		"ecdsa_secp256k1_sha-256":    ES256K,
+		"ed25519":                    EdDSA,
+		"ed448":                      EdDSA,
+		"rsa_pkcs1v15_sha-256":       RS256,
+	} {
+		alg, err := AlgorithmForSuite(suiteType)
+		if err != nil || alg != expected {
+			t.Errorf("Got %s %v, expected %s", alg, err, expected)
+		}
+	}
+	for _, suiteType := range []string{"ecdsa_secp256k1_keccak256", "ed25519ph", "ecdsa_P256_sha3-256", "none"} {
+		if _, err := AlgorithmForSuite(suiteType); !errors.Is(err, ErrAlgorithm) {
+			t.Errorf("Expected ErrAlgorithm for %s, got %v", suiteType, err)
+		}
+	}
+}
+
+func TestCompactRFC8037(t *testing.T) {
+	const token = "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc." +
+		"hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+	signer, verifier := newTestPair(t, testKeys(t)[EdDSA])
+	result, err := SignCompact(signer, Header{}, []byte("Example of Ed25519 signing"))
+	if err != nil {
+		t.Fatalf("Error signing: %s", err)
+	}
+	if result != token {
+		t.Errorf("Got %s, expected %s", result, token)
+	}
+	payload, header, err := VerifyCompact(verifier, token)
+	if err != nil {
+		t.Fatalf("Error verifying: %s", err)
+	}
+	if string(payload) != "Example of Ed25519 signing" || header.Algorithm != EdDSA {
+		t.Errorf("Got %s %+v", payload, header)
+	}
+}
+
+func TestCompactRoundTrip(t *testing.T) {
+	for alg, key := range testKeys(t) {
+		t.Run(alg, func(t *testing.T) {
+			signer, verifier := newTestPair(t, key)
+			token, err := SignCompact(signer, Header{KeyID: "key-1"}, []byte("payload"))
+			if err != nil {
+				t.Fatalf("Error signing: %s", err)
+			}
+			payload, header, err := VerifyCompact(verifier, token)
+			if err != nil {
+				t.Fatalf("Error verifying: %s", err)
+			}
+			if string(payload) != "payload" {
+				t.Errorf("Got %s, expected payload", payload)
+			}
+			if header.Algorithm != alg || header.KeyID != "key-1" {
+				t.Errorf("Got %+v, expected alg %s and kid key-1", header, alg)
+			}
+
+			segments := strings.Split(token, ".")
+			tampered := segments[0] + "." + base64.RawURLEncoding.EncodeToString([]byte("other")) + "." + segments[2]
+			if _, _, err := VerifyCompact(verifier, tampered); !errors.Is(err, ErrSignature) {
+				t.Errorf("Expected ErrSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func encodeHeader(header string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(header))
+}
+
+func TestCompactAlgorithmConfusion(t *testing.T) {
+	keys := testKeys(t)
+	edSigner, edVerifier := newTestPair(t, keys[EdDSA])
+	_, esVerifier := newTestPair(t, keys[ES256])
+	payload := base64.RawURLEncoding.EncodeToString([]byte("payload"))
+
+	token, _ := SignCompact(edSigner, Header{}, []byte("payload"))
+	if _, _, err := VerifyCompact(esVerifier, token); !errors.Is(err, ErrAlgorithm) {
+		t.Errorf("Expected ErrAlgorithm for EdDSA token and ES256 verifier, got %v", err)
+	}
+
+	// A valid EdDSA signature under a header naming another algorithm.
+	for _, alg := range []string{"none", "HS256", "ES256", "eddsa"} {
+		header := encodeHeader(`{"alg":"` + alg + `"}`)
+		signature, _ := edSigner.Sign([]byte(header + "." + payload))
+		forged := header + "." + payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+		if _, _, err := VerifyCompact(edVerifier, forged); !errors.Is(err, ErrAlgorithm) {
+			t.Errorf("Expected ErrAlgorithm for alg %s, got %v", alg, err)
+		}
+	}
+	if _, _, err := VerifyCompact(edVerifier, encodeHeader(`{"alg":"none"}`)+"."+payload+"."); !errors.Is(err, ErrAlgorithm) {
+		t.Errorf("Expected ErrAlgorithm for unsigned token, got %v", err)
+	}
+
+	if _, err := SignCompact(edSigner, Header{Algorithm: ES256}, nil); !errors.Is(err, ErrAlgorithm) {
+		t.Errorf("Expected ErrAlgorithm signing with a mismatched header, got %v", err)
+	}
+	keccakSigner, _ := crypto.NewSecp256k1Keccak256Signer(mustHex(secp256k1PrivHex))
+	if _, err := SignCompact(keccakSigner, Header{}, nil); !errors.Is(err, ErrAlgorithm) {
+		t.Errorf("Expected ErrAlgorithm for a suite without an algorithm, got %v", err)
+	}
+}
+
+func TestVerifyCompactInvalid(t *testing.T) {
+	edSigner, edVerifier := newTestPair(t, testKeys(t)[EdDSA])
+	payload := base64.RawURLEncoding.EncodeToString([]byte("payload"))
+	sign := func(header string) string {
+		encoded := encodeHeader(header)
+		signature, _ := edSigner.Sign([]byte(encoded + "." + payload))
+		return encoded + "." + payload + "." + base64.RawURLEncoding.EncodeToString(signature)
+	}
+	token, _ := SignCompact(edSigner, Header{}, []byte("payload"))
+
+	badTokens := []struct {
+		name     string
+		token    string
+		expected error
+	}{
+		{"two_segments", "a.b", ErrMalformed},
+		{"four_segments", token + ".a", ErrMalformed},
+		{"padded_base64", token + "==", ErrMalformed},
+		{"standard_base64", strings.Replace(token, "-", "+", 1) + "+/", ErrMalformed},
+		{"header_not_object", sign(`"EdDSA"`), ErrMalformed},
+		{"header_not_json", sign(`{"alg":`), ErrMalformed},
+		{"missing_alg", sign(`{"kid":"1"}`), ErrMalformed},
+		{"alg_not_string", sign(`{"alg":1}`), ErrMalformed},
+		{"alg_capitalized", sign(`{"Alg":"EdDSA"}`), ErrMalformed},
+		{"alg_upper_case", sign(`{"ALG":"EdDSA"}`), ErrMalformed},
+		{"kid_not_string", sign(`{"alg":"EdDSA","kid":1}`), ErrMalformed},
+		{"critical", sign(`{"alg":"EdDSA","crit":["b64"],"b64":false}`), ErrCritical},
+	}
+	for _, tt := range badTokens {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := VerifyCompact(edVerifier, tt.token); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestJSONGeneral(t *testing.T) {
+	keys := testKeys(t)
+	edSigner, edVerifier := newTestPair(t, keys[EdDSA])
+	k1Signer, k1Verifier := newTestPair(t, keys[ES256K])
+	_, esVerifier := newTestPair(t, keys[ES256])
+
+	data, err := SignJSON([]byte("payload"),
+		JSONSigner{edSigner, Header{KeyID: "ed"}},
+		JSONSigner{k1Signer, Header{KeyID: "k1"}},
+	)
+	if err != nil {
+		t.Fatalf("Error signing: %s", err)
+	}
+	var decoded map[string]json.RawMessage
+	json.Unmarshal(data, &decoded)
+	if _, ok := decoded["signatures"]; !ok || len(decoded) != 2 {
+		t.Errorf("Expected general serialization, got %s", data)
+	}
+
+	for kid, verifier := range map[string]crypto.Verifier{"ed": edVerifier, "k1": k1Verifier} {
+		payload, header, err := VerifyJSON(verifier, data)
+		if err != nil {
+			t.Fatalf("Error verifying: %s", err)
+		}
+		if string(payload) != "payload" || header.KeyID != kid {
+			t.Errorf("Got %s %+v, expected kid %s", payload, header, kid)
+		}
+	}
+	if _, _, err := VerifyJSON(esVerifier, data); !errors.Is(err, ErrAlgorithm) {
+		t.Errorf("Expected ErrAlgorithm, got %v", err)
+	}
+
+	// Another ed25519 key finds a signature with its algorithm that does not
+	// verify.
+	otherPub, _, _ := crypto.GenerateEd25519KeyPair()
+	otherVerifier, _ := crypto.NewEd25519Verifier(otherPub)
+	if _, _, err := VerifyJSON(otherVerifier, data); !errors.Is(err, ErrSignature) {
+		t.Errorf("Expected ErrSignature, got %v", err)
+	}
+}
+
+func TestJSONFlattened(t *testing.T) {
+	edSigner, edVerifier := newTestPair(t, testKeys(t)[EdDSA])
+	data, err := SignJSON([]byte("payload"), JSONSigner{edSigner, Header{}})
+	if err != nil {
+		t.Fatalf("Error signing: %s", err)
+	}
+	var decoded map[string]json.RawMessage
+	json.Unmarshal(data, &decoded)
+	if _, ok := decoded["signatures"]; ok {
+		t.Errorf("Expected flattened serialization, got %s", data)
+	}
+
+	// Compact and flattened serializations of the same JWS.
+	token, _ := SignCompact(edSigner, Header{}, []byte("payload"))
+	segments := strings.Split(token, ".")
+	expected := `{"payload":"` + segments[1] + `","protected":"` + segments[0] + `","signature":"` + segments[2] + `"}`
+	if string(data) != expected {
+		t.Errorf("Got %s, expected %s", data, expected)
+	}
+
+	withKid := `{"payload":"` + segments[1] + `","protected":"` + segments[0] +
+		`","header":{"kid":"unprotected"},"signature":"` + segments[2] + `"}`
+	_, header, err := VerifyJSON(edVerifier, []byte(withKid))
+	if err != nil {
+		t.Fatalf("Error verifying: %s", err)
+	}
+	if header.KeyID != "unprotected" {
+		t.Errorf("Got kid %s, expected unprotected", header.KeyID)
+	}
+}
+
+func TestVerifyJSONInvalid(t *testing.T) {
+	edSigner, edVerifier := newTestPair(t, testKeys(t)[EdDSA])
+	token, _ := SignCompact(edSigner, Header{}, []byte("payload"))
+	segments := strings.Split(token, ".")
+	p, h, s := segments[1], segments[0], segments[2]
+
+	badJWS := []struct {
+		name     string
+		jws      string
+		expected error
+	}{
+		{"not_json", `{`, ErrMalformed},
+		{"missing_payload", `{"protected":"` + h + `","signature":"` + s + `"}`, ErrMalformed},
+		{"no_signatures", `{"payload":"` + p + `"}`, ErrMalformed},
+		{"both_forms", `{"payload":"` + p + `","protected":"` + h + `","signature":"` + s +
+			`","signatures":[{"protected":"` + h + `","signature":"` + s + `"}]}`, ErrMalformed},
+		{"unprotected_alg", `{"payload":"` + p + `","header":{"alg":"EdDSA"},"signature":"` + s + `"}`, ErrMalformed},
+		{"alg_in_both", `{"payload":"` + p + `","protected":"` + h +
+			`","header":{"alg":"EdDSA"},"signature":"` + s + `"}`, ErrMalformed},
+		{"unprotected_crit", `{"payload":"` + p + `","protected":"` + h +
+			`","header":{"crit":["exp"]},"signature":"` + s + `"}`, ErrMalformed},
+	}
+	for _, tt := range badJWS {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := VerifyJSON(edVerifier, []byte(tt.jws)); !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	// Header parameter names are case sensitive, unprotected ALG and KID are
+	// unknown parameters and do not replace the protected alg and kid.
+	sign := func(protected string) (string, string) {
+		encoded := encodeHeader(protected)
+		signature, _ := edSigner.Sign([]byte(encoded + "." + p))
+		return encoded, base64.RawURLEncoding.EncodeToString(signature)
+	}
+	noneHeader, noneSignature := sign(`{"alg":"none"}`)
+	for _, unprotected := range []string{`{"ALG":"EdDSA"}`, `{"Alg":"EdDSA","KID":"evil"}`} {
+		jws := `{"payload":"` + p + `","protected":"` + noneHeader +
+			`","header":` + unprotected + `,"signature":"` + noneSignature + `"}`
+		if _, _, err := VerifyJSON(edVerifier, []byte(jws)); !errors.Is(err, ErrAlgorithm) {
+			t.Errorf("Expected ErrAlgorithm for unprotected %s, got %v", unprotected, err)
+		}
+	}
+	kidHeader, kidSignature := sign(`{"alg":"EdDSA","kid":"good"}`)
+	jws := `{"payload":"` + p + `","protected":"` + kidHeader +
+		`","header":{"KID":"evil","Kid":"evil"},"signature":"` + kidSignature + `"}`
+	_, header, err := VerifyJSON(edVerifier, []byte(jws))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if header.KeyID != "good" {
+		t.Errorf("Got %v, expected %v", header.KeyID, "good")
+	}
+
+	if _, err := SignJSON([]byte("payload")); err == nil {
+		t.Errorf("Expected an error signing without signers.")
+	}
+}
@@ -0,0 +1,229 @@
+package jose
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/kochavalabs/crypto"
+)
+
+// NumericDate is a JWT time, seconds since the Unix epoch.
+type NumericDate int64
+
+// The range of dates accepted in claims, years 1 to 9999. Dates outside it
+// can not be compared reliably as a time.Time.
+const (
+	minNumericDate NumericDate = -62135596800 // 0001-01-01T00:00:00Z
+	maxNumericDate NumericDate = 253402300799 // 9999-12-31T23:59:59Z
+)
+
+// NewNumericDate returns t as a NumericDate, truncated to the second.
+func NewNumericDate(t time.Time) *NumericDate {
+	date := NumericDate(t.Unix())
+	return &date
+}
+
+// Time returns the date as a time.Time. Dates before year 1 or after year
+// 9999 are clamped to that range.
+func (d NumericDate) Time() time.Time {
+	if d < minNumericDate {
+		d = minNumericDate
+	} else if d > maxNumericDate {
+		d = maxNumericDate
+	}
+	return time.Unix(int64(d), 0)
+}
+
+// UnmarshalJSON accepts integer and fractional dates, dropping the fraction.
+// Dates before year 1 or after year 9999 are rejected.
+func (d *NumericDate) UnmarshalJSON(data []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(data, &seconds); err != nil {
+		return err
+	}
+	if math.IsNaN(seconds) || seconds >= 1<<63 || seconds < -(1<<63) {
+		return fmt.Errorf("%w: date out of range", ErrMalformed)
+	}
+	date := NumericDate(math.Floor(seconds))
+	if date < minNumericDate || date > maxNumericDate {
+		return fmt.Errorf("%w: date %d out of range", ErrMalformed, date)
+	}
+	*d = date
+	return nil
+}
+
+// Audience is the aud claim, a single string or an array of strings in JSON.
+type Audience []string
+
+// MarshalJSON encodes a single audience as a string.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	if len(a) == 1 {
+		return json.Marshal(a[0])
+	}
+	return json.Marshal([]string(a))
+}
+
+// UnmarshalJSON decodes a string or an array of strings.
+func (a *Audience) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*a = Audience{single}
+		return nil
+	}
+	var multiple []string
+	if err := json.Unmarshal(data, &multiple); err != nil {
+		return err
+	}
+	*a = multiple
+	return nil
+}
+
+// Contains returns true if audience is one of the values.
+func (a Audience) Contains(audience string) bool {
+	for _, value := range a {
+		if value == audience {
+			return true
+		}
+	}
+	return false
+}
+
+// Claims are the registered claims of a JWT. Tokens with private claims can
+// embed Claims in their own struct.
+type Claims struct {
+	Issuer    string       `json:"iss,omitempty"`
+	Subject   string       `json:"sub,omitempty"`
+	Audience  Audience     `json:"aud,omitempty"`
+	ExpiresAt *NumericDate `json:"exp,omitempty"`
+	NotBefore *NumericDate `json:"nbf,omitempty"`
+	IssuedAt  *NumericDate `json:"iat,omitempty"`
+	ID        string       `json:"jti,omitempty"`
+}
+
+// Validation lists the checks VerifyJWT makes on the registered claims.
+type Validation struct {
+	// Issuer, if set, must equal the iss claim.
+	Issuer string
+	// Audience must be one of the values of the aud claim. Tokens with an aud
+	// claim are rejected when it is empty, as RFC 7519 requires.
+	Audience string
+	// RequireExpiry rejects tokens without an exp claim.
+	RequireExpiry bool
+	// Leeway allows for clock skew when checking exp and nbf.
+	Leeway time.Duration
+	// Now is the time tokens are checked at, the current time if zero.
+	Now time.Time
+}
+
+// Validate checks claims against the validation options.
+func (v Validation) Validate(claims *Claims) error {
+	now := v.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+	if claims.ExpiresAt == nil {
+		if v.RequireExpiry {
+			return fmt.Errorf("%w: exp", ErrMissingClaim)
+		}
+	} else if !now.Before(claims.ExpiresAt.Time().Add(v.Leeway)) {
+		return ErrExpired
+	}
+	if claims.NotBefore != nil && now.Add(v.Leeway).Before(claims.NotBefore.Time()) {
+		return ErrNotYetValid
+	}
+	if v.Issuer != "" && claims.Issuer != v.Issuer {
+		return fmt.Errorf("%w: %q", ErrIssuer, claims.Issuer)
+	}
+	if len(claims.Audience) > 0 || v.Audience != "" {
+		if v.Audience == "" || !claims.Audience.Contains(v.Audience) {
+			return fmt.Errorf("%w: %q", ErrAudience, []string(claims.Audience))
+		}
+	}
+	return nil
+}
+
+// SignJWT signs claims, any value that encodes to a JSON object, as a JWT in
+// the compact serialization. The typ header defaults to JWT.
+func SignJWT(signer crypto.Signer, header Header, claims interface{}) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	if len(payload) == 0 || payload[0] != '{' {
+		return "", fmt.Errorf("%w: claims must encode to a JSON object", ErrMalformed)
+	}
+	if header.Type == "" {
+		header.Type = "JWT"
+	}
+	return SignCompact(signer, header, payload)
+}
+
+// VerifyJWT verifies a JWT and validates its registered claims, returning
+// them. When claims is not nil the payload is also decoded into it with
+// json.Unmarshal, which matches names case insensitively, the returned claims
+// are the ones validated. Nested JWTs are not supported.
+func VerifyJWT(
+	verifier crypto.Verifier,
+	token string,
+	validation Validation,
+	claims interface{},
+) (*Claims, error) {
+	payload, header, err := VerifyCompact(verifier, token)
+	if err != nil {
+		return nil, err
+	}
+	if header.Type != "" && !strings.EqualFold(header.Type, "JWT") &&
+		!strings.EqualFold(header.Type, "application/jwt") {
+		return nil, fmt.Errorf("%w: %q", ErrType, header.Type)
+	}
+	if strings.EqualFold(header.ContentType, "JWT") {
+		return nil, fmt.Errorf("%w: nested JWT", ErrType)
+	}
+
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(payload, &object); err != nil || object == nil {
+		return nil, fmt.Errorf("%w: claims are not a JSON object", ErrMalformed)
+	}
+	registered, err := claimsFromObject(object)
+	if err != nil {
+		return nil, err
+	}
+	if err := validation.Validate(registered); err != nil {
+		return nil, err
+	}
+	if claims != nil {
+		if err := json.Unmarshal(payload, claims); err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrMalformed, err)
+		}
+	}
+	return registered, nil
+}
+
+// Decodes the registered claims from the members of the payload. Claim names
+// are case sensitive, unlike the field names json.Unmarshal matches, so e.g.
+// "EXP" is a private claim and never sets ExpiresAt.
+func claimsFromObject(object map[string]json.RawMessage) (*Claims, error) {
+	claims := &Claims{}
+	fields := map[string]interface{}{
+		"iss": &claims.Issuer,
+		"sub": &claims.Subject,
+		"aud": &claims.Audience,
+		"exp": &claims.ExpiresAt,
+		"nbf": &claims.NotBefore,
+		"iat": &claims.IssuedAt,
+		"jti": &claims.ID,
+	}
+	for name, field := range fields {
+		value, ok := object[name]
+		if !ok {
+			continue
+		}
+		if err := json.Unmarshal(value, field); err != nil {
+			return nil, fmt.Errorf("%w: claim %s: %s", ErrMalformed, name, err)
+		}
+	}
+	return claims, nil
+}
@@ -0,0 +1,218 @@
+package jose
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type testClaims struct {
+	Claims
+	Role string `json:"role"`
+}
+
+func TestJWTRoundTrip(t *testing.T) {
+	signer, verifier := newTestPair(t, testKeys(t)[ES256K])
+	now := time.Unix(1700000000, 0)
+	claims := testClaims{
+		Claims: Claims{
+			Issuer:    "issuer",
+			Subject:   "subject",
+			Audience:  Audience{"service"},
+			ExpiresAt: NewNumericDate(now.Add(time.Hour)),
+			IssuedAt:  NewNumericDate(now),
+		},
+		Role: "admin",
+	}
+	token, err := SignJWT(signer, Header{KeyID: "k1"}, claims)
+	if err != nil {
+		t.Fatalf("Error signing: %s", err)
+	}
+	header, _ := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	if string(header) != `{"alg":"ES256K","kid":"k1","typ":"JWT"}` {
+		t.Errorf("Got header %s", header)
+	}
+
+	var decoded testClaims
+	registered, err := VerifyJWT(verifier, token, Validation{
+		Issuer:        "issuer",
+		Audience:      "service",
+		RequireExpiry: true,
+		Now:           now,
+	}, &decoded)
+	if err != nil {
+		t.Fatalf("Error verifying: %s", err)
+	}
+	if registered.Subject != "subject" || decoded.Role != "admin" || decoded.Subject != "subject" {
+		t.Errorf("Got %+v and %+v", registered, decoded)
+	}
+	if registered.ExpiresAt.Time() != now.Add(time.Hour) {
+		t.Errorf("Got exp %s, expected %s", registered.ExpiresAt.Time(), now.Add(time.Hour))
+	}
+}
+
+func TestValidation(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	at := func(offset time.Duration) *NumericDate { return NewNumericDate(now.Add(offset)) }
+
+	cases := []struct {
+		name       string
+		claims     Claims
+		validation Validation
+		expected   error
+	}{
+		{"no_claims", Claims{}, Validation{Now: now}, nil},
+		{"valid_window", Claims{ExpiresAt: at(time.Minute), NotBefore: at(-time.Minute)}, Validation{Now: now}, nil},
+		{"expired", Claims{ExpiresAt: at(-time.Second)}, Validation{Now: now}, ErrExpired},
+		{"expires_now", Claims{ExpiresAt: at(0)}, Validation{Now: now}, ErrExpired},
+		{"expired_within_leeway", Claims{ExpiresAt: at(-time.Second)}, Validation{Now: now, Leeway: time.Minute}, nil},
+		{"not_yet_valid", Claims{NotBefore: at(time.Second)}, Validation{Now: now}, ErrNotYetValid},
+		{"not_yet_valid_within_leeway", Claims{NotBefore: at(time.Second)}, Validation{Now: now, Leeway: time.Minute}, nil},
+		{"missing_exp", Claims{}, Validation{Now: now, RequireExpiry: true}, ErrMissingClaim},
+		{"issuer", Claims{Issuer: "a"}, Validation{Now: now, Issuer: "a"}, nil},
+		{"wrong_issuer", Claims{Issuer: "b"}, Validation{Now: now, Issuer: "a"}, ErrIssuer},
+		{"missing_issuer", Claims{}, Validation{Now: now, Issuer: "a"}, ErrIssuer},
+		{"audience", Claims{Audience: Audience{"x", "y"}}, Validation{Now: now, Audience: "y"}, nil},
+		{"wrong_audience", Claims{Audience: Audience{"x"}}, Validation{Now: now, Audience: "y"}, ErrAudience},
+		{"missing_audience", Claims{}, Validation{Now: now, Audience: "y"}, ErrAudience},
+		{"unexpected_audience", Claims{Audience: Audience{"x"}}, Validation{Now: now}, ErrAudience},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validation.Validate(&tt.claims)
+			if tt.expected == nil && err != nil {
+				t.Errorf("Unexpected error: %s", err)
+			}
+			if tt.expected != nil && !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func TestVerifyJWTInvalid(t *testing.T) {
+	signer, verifier := newTestPair(t, testKeys(t)[EdDSA])
+	sign := func(header Header, payload string) string {
+		token, err := SignCompact(signer, header, []byte(payload))
+		if err != nil {
+			t.Fatalf("Error signing: %s", err)
+		}
+		return token
+	}
+
+	badTokens := []struct {
+		name     string
+		token    string
+		expected error
+	}{
+		{"wrong_type", sign(Header{Type: "at+jwt"}, `{}`), ErrType},
+		{"nested", sign(Header{ContentType: "JWT"}, `{}`), ErrType},
+		{"array_payload", sign(Header{}, `[1]`), ErrMalformed},
+		{"null_payload", sign(Header{}, `null`), ErrMalformed},
+		{"bad_exp", sign(Header{}, `{"exp":"soon"}`), ErrMalformed},
+		{"bad_aud", sign(Header{}, `{"aud":1}`), ErrMalformed},
+		{"expired", sign(Header{}, `{"exp":1}`), ErrExpired},
+		{"nbf_near_max_int64", sign(Header{}, `{"nbf":9223372036854775000}`), ErrMalformed},
+		{"nbf_two_to_63", sign(Header{}, `{"nbf":9223372036854775808}`), ErrMalformed},
+		{"nbf_after_9999", sign(Header{}, `{"nbf":253402300800}`), ErrMalformed},
+		{"nbf_9999", sign(Header{}, `{"nbf":253402300799}`), ErrNotYetValid},
+		{"exp_near_min_int64", sign(Header{}, `{"exp":-9223372036854775000}`), ErrMalformed},
+		{"exp_before_year_1", sign(Header{}, `{"exp":-62135596801}`), ErrMalformed},
+		{"exp_year_1", sign(Header{}, `{"exp":-62135596800}`), ErrExpired},
+		{"exp_infinite", sign(Header{}, `{"exp":1e400}`), ErrMalformed},
+	}
+	for _, tt := range badTokens {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := VerifyJWT(verifier, tt.token, Validation{}, nil)
+			if !errors.Is(err, tt.expected) {
+				t.Errorf("Expected %v, got %v", tt.expected, err)
+			}
+		})
+	}
+
+	token := sign(Header{Type: "JWT"}, `{"exp":1.9}`)
+	claims, err := VerifyJWT(verifier, token, Validation{Now: time.Unix(0, 0)}, nil)
+	if err != nil || *claims.ExpiresAt != 1 {
+		t.Errorf("Expected fractional exp to be truncated, got %v %v", claims, err)
+	}
+
+	if _, err := SignJWT(signer, Header{}, "not an object"); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected ErrMalformed for non object claims, got %v", err)
+	}
+}
+
+func TestVerifyJWTClaimNamesAreCaseSensitive(t *testing.T) {
+	signer, verifier := newTestPair(t, testKeys(t)[EdDSA])
+	now := time.Unix(1700000000, 0)
+	token, err := SignCompact(signer, Header{Type: "JWT"},
+		[]byte(`{"exp":1000,"EXP":99999999999,"aud":"a","AUD":"b","Iss":"other"}`))
+	if err != nil {
+		t.Fatalf("Error signing: %s", err)
+	}
+	if _, err := VerifyJWT(verifier, token, Validation{Now: now, Audience: "a"}, nil); !errors.Is(err, ErrExpired) {
+		t.Errorf("Expected %v, got %v", ErrExpired, err)
+	}
+
+	claims, err := VerifyJWT(verifier, token, Validation{Now: time.Unix(0, 0), Audience: "a"}, nil)
+	if err != nil {
+		t.Fatalf("Error verifying: %s", err)
+	}
+	if *claims.ExpiresAt != 1000 || !reflect.DeepEqual(claims.Audience, Audience{"a"}) || claims.Issuer != "" {
+		t.Errorf("Got %+v", claims)
+	}
+	if _, err := VerifyJWT(verifier, token, Validation{Now: time.Unix(0, 0), Audience: "b"}, nil); !errors.Is(err, ErrAudience) {
+		t.Errorf("Expected %v, got %v", ErrAudience, err)
+	}
+}
+
+func TestNumericDateTime(t *testing.T) {
+	cases := []struct {
+		date     NumericDate
+		expected time.Time
+	}{
+		{0, time.Unix(0, 0)},
+		{maxNumericDate, time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{math.MaxInt64, time.Date(9999, 12, 31, 23, 59, 59, 0, time.UTC)},
+		{minNumericDate, time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{math.MinInt64, time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range cases {
+		if got := tt.date.Time(); !got.Equal(tt.expected) {
+			t.Errorf("Got %v, expected %v", got, tt.expected)
+		}
+	}
+
+	// A far future exp or nbf set in Go stays in the future.
+	now := time.Unix(1700000000, 0)
+	far := NumericDate(math.MaxInt64)
+	if err := (Validation{Now: now}).Validate(&Claims{ExpiresAt: &far}); err != nil {
+		t.Errorf("Unexpected error: %s", err)
+	}
+	if err := (Validation{Now: now}).Validate(&Claims{NotBefore: &far}); !errors.Is(err, ErrNotYetValid) {
+		t.Errorf("Expected %v, got %v", ErrNotYetValid, err)
+	}
+}
+
+func TestAudienceJSON(t *testing.T) {
+	for _, tt := range []struct {
+		audience Audience
+		json     string
+	}{
+		{Audience{"a"}, `"a"`},
+		{Audience{"a", "b"}, `["a","b"]`},
+	} {
+		encoded, _ := json.Marshal(tt.audience)
+		if string(encoded) != tt.json {
+			t.Errorf("Got %s, expected %s", encoded, tt.json)
+		}
+		var decoded Audience
+		if err := json.Unmarshal([]byte(tt.json), &decoded); err != nil || len(decoded) != len(tt.audience) {
+			t.Errorf("Got %v %v, expected %v", decoded, err, tt.audience)
+		}
+	}
+}
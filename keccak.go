@@ -1,6 +1,8 @@
 package crypto
 
 import (
+	"hash"
+
 	"golang.org/x/crypto/sha3"
 )
 
@@ -20,3 +22,7 @@ func (h *Keccak256Hasher) Hash(input ...[]byte) []byte {
 func (h *Keccak256Hasher) HashHex(input ...[]byte) string {
 	return hashHex(sha3.NewLegacyKeccak256(), input...)
 }
+
+func (h *Keccak256Hasher) newHash() hash.Hash {
+	return sha3.NewLegacyKeccak256()
+}
@@ -0,0 +1,101 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// Returns the X || Y encoding of an ECDSA public key, the format taken by the
+// ECDSA verifier constructors.
+func ecdsaPublicKeyBytes(pub *ecdsa.PublicKey) []byte {
+	coordinateLength := (pub.Curve.Params().BitSize + 7) / 8
+	pubKey := make([]byte, 2*coordinateLength)
+	pub.X.FillBytes(pubKey[:coordinateLength])
+	pub.Y.FillBytes(pubKey[coordinateLength:])
+	return pubKey
+}
+
+// VerifierFromECDSAPublicKey constructs a Verifier of the given suite type for
+// an ECDSA public key, e.g. one loaded with PublicKeyFromPEMFile. The suite
+// must be an ECDSA suite over the key's curve, otherwise ErrUnexpectedSuite is
+// returned. secp256k1 keys must use the curve secp256k1.S256().
+func VerifierFromECDSAPublicKey(pub *ecdsa.PublicKey, suiteType string) (Verifier, error) {
+	if _, err := lookupSuite(suiteType); err != nil {
+		return nil, err
+	}
+	if pub == nil || pub.Curve == nil || pub.X == nil || pub.Y == nil {
+		return nil, fmt.Errorf("%w: missing ECDSA public key", ErrInvalidPublicKey)
+	}
+	if !pub.Curve.IsOnCurve(pub.X, pub.Y) {
+		return nil, fmt.Errorf("%w: point is not on the %s curve", ErrInvalidPublicKey, pub.Curve.Params().Name)
+	}
+	mismatch := fmt.Errorf(
+		"%w: %s is not an ECDSA suite over the %s curve",
+		ErrUnexpectedSuite, suiteType, pub.Curve.Params().Name)
+	verifier, err := NewVerifierForSuite(suiteType, ecdsaPublicKeyBytes(pub))
+	if err != nil {
+		return nil, mismatch
+	}
+	switch v := verifier.(type) {
+	case *EcdsaVerifier:
+		if v.publicKey.Curve == pub.Curve {
+			return verifier, nil
+		}
+	case *secp256k1Verifier:
+		if pub.Curve == secp256k1.S256() {
+			return verifier, nil
+		}
+	}
+	return nil, mismatch
+}
+
+// SignerFromECDSAPrivateKey constructs a Signer of the given suite type for an
+// ECDSA private key, e.g. one loaded with PrivateKeyFromPEMFile. The suite must
+// be an ECDSA suite over the key's curve, as for VerifierFromECDSAPublicKey,
+// and must be able to sign.
+func SignerFromECDSAPrivateKey(priv *ecdsa.PrivateKey, suiteType string) (Signer, error) {
+	if priv == nil || priv.D == nil {
+		return nil, errors.New("missing ECDSA private key")
+	}
+	if _, err := VerifierFromECDSAPublicKey(&priv.PublicKey, suiteType); err != nil {
+		return nil, err
+	}
+	if priv.D.Sign() <= 0 || priv.D.Cmp(priv.Curve.Params().N) >= 0 {
+		return nil, errors.New("ECDSA private key is out of range")
+	}
+	privData := priv.D.FillBytes(make([]byte, ecdsaScalarLength(priv.Curve)))
+	signer, err := NewSignerForSuite(suiteType, privData)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(signer.PublicKey(), ecdsaPublicKeyBytes(&priv.PublicKey)) {
+		return nil, fmt.Errorf("%w: public key does not match the private key", ErrInvalidPublicKey)
+	}
+	return signer, nil
+}
+
+// SignerFromPEMFile reads an ECDSA private key with PrivateKeyFromPEMFile and
+// constructs a Signer of the given suite type for it, see
+// SignerFromECDSAPrivateKey.
+func SignerFromPEMFile(fileName string, suiteType string) (Signer, error) {
+	priv, err := PrivateKeyFromPEMFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return SignerFromECDSAPrivateKey(priv, suiteType)
+}
+
+// VerifierFromPEMFile reads an ECDSA public key with PublicKeyFromPEMFile and
+// constructs a Verifier of the given suite type for it, see
+// VerifierFromECDSAPublicKey.
+func VerifierFromPEMFile(fileName string, suiteType string) (Verifier, error) {
+	pub, err := PublicKeyFromPEMFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	return VerifierFromECDSAPublicKey(pub, suiteType)
+}
@@ -0,0 +1,172 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+func ecdsaKeyFromHex(t *testing.T, curve elliptic.Curve, privHex string) *ecdsa.PrivateKey {
+	t.Helper()
+	privData, err := FromHex(privHex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	priv := &ecdsa.PrivateKey{D: new(big.Int).SetBytes(privData)}
+	priv.PublicKey.Curve = curve
+	priv.PublicKey.X, priv.PublicKey.Y = curve.ScalarBaseMult(privData)
+	return priv
+}
+
+func TestSignerFromECDSAPrivateKey(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	k256 := ecdsaKeyFromHex(t, secp256k1.S256(), Secp256k1PrivHex)
+	var cases = []struct {
+		priv      *ecdsa.PrivateKey
+		suiteType string
+	}{
+		{p256, "ecdsa_P256_sha-256_rfc6979"},
+		{p256, "ecdsa_P256_sha-256_indet"},
+		{p256, "ecdsa_P256_sha3-256_det"},
+		{p256, "ecdsa_P256_shake256_rfc6979"},
+		{p384, "ecdsa_P384_sha-384_rfc6979"},
+		{p521, "ecdsa_P521_sha-512_rfc6979"},
+		{k256, "ecdsa_secp256k1_sha-256"},
+		{k256, "ecdsa_secp256k1_keccak256"},
+	}
+	message := []byte("message")
+	for _, tc := range cases {
+		signer, err := SignerFromECDSAPrivateKey(tc.priv, tc.suiteType)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tc.suiteType, err)
+			continue
+		}
+		if signer.SuiteType() != tc.suiteType {
+			t.Errorf("Got %v, expected %v", signer.SuiteType(), tc.suiteType)
+		}
+		signature, err := signer.Sign(message)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tc.suiteType, err)
+			continue
+		}
+		verifier, err := VerifierFromECDSAPublicKey(&tc.priv.PublicKey, tc.suiteType)
+		if err != nil {
+			t.Errorf("%s: unexpected error %v", tc.suiteType, err)
+			continue
+		}
+		if !verifier.Verify(message, signature) {
+			t.Errorf("%s: signature should verify", tc.suiteType)
+		}
+		if verifier.(PublicKeyHolder).KeyID() != signer.KeyID() {
+			t.Errorf("%s: verifier and signer key IDs differ", tc.suiteType)
+		}
+	}
+}
+
+func TestSignerFromECDSAPrivateKeyErrors(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	k256 := ecdsaKeyFromHex(t, secp256k1.S256(), Secp256k1PrivHex)
+	// A P256 key whose public key is the base point, not the one of D.
+	otherPub := *p256
+	otherPub.PublicKey.X, otherPub.PublicKey.Y = elliptic.P256().ScalarBaseMult([]byte{1})
+	var cases = []struct {
+		name      string
+		priv      *ecdsa.PrivateKey
+		suiteType string
+		err       error
+	}{
+		{"unknown suite", p256, "ecdsa_P256_unknown", ErrUnknownSuite},
+		{"other curve", p256, "ecdsa_P384_sha-384_rfc6979", ErrUnexpectedSuite},
+		{"larger curve", p384, "ecdsa_P256_sha-256_rfc6979", ErrUnexpectedSuite},
+		{"secp256k1 suite", p256, "ecdsa_secp256k1_sha-256", ErrUnexpectedSuite},
+		{"secp256k1 key", k256, "ecdsa_P256_sha-256_rfc6979", ErrUnexpectedSuite},
+		{"ed25519", p256, "ed25519", ErrUnexpectedSuite},
+		{"rsa", p256, "rsa_pss_sha-256", ErrUnexpectedSuite},
+		{"verify only", p256, "ecdsa_P256_sha-256", ErrSuiteCannotSign},
+		{"mismatched public key", &otherPub, "ecdsa_P256_sha-256_rfc6979", ErrInvalidPublicKey},
+	}
+	for _, tc := range cases {
+		_, err := SignerFromECDSAPrivateKey(tc.priv, tc.suiteType)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: Got %v, expected %v", tc.name, err, tc.err)
+		}
+	}
+	if _, err := SignerFromECDSAPrivateKey(nil, "ecdsa_P256_sha-256_rfc6979"); err == nil {
+		t.Error("nil key should not create a signer")
+	}
+}
+
+func TestVerifierFromECDSAPublicKeyErrors(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	offCurve := p256.PublicKey
+	offCurve.Y = new(big.Int).Add(offCurve.Y, big.NewInt(1))
+	var cases = []struct {
+		name      string
+		pub       *ecdsa.PublicKey
+		suiteType string
+		err       error
+	}{
+		{"verify only suite", &p256.PublicKey, "ecdsa_P256_sha-256", nil},
+		{"signer suite", &p256.PublicKey, "ecdsa_P256_sha3-256_det", nil},
+		{"unknown suite", &p256.PublicKey, "ecdsa_P256_unknown", ErrUnknownSuite},
+		{"other curve", &p256.PublicKey, "ecdsa_P521_sha-512", ErrUnexpectedSuite},
+		{"ed25519", &p256.PublicKey, "ed25519", ErrUnexpectedSuite},
+		{"off curve", &offCurve, "ecdsa_P256_sha-256", ErrInvalidPublicKey},
+		{"nil key", nil, "ecdsa_P256_sha-256", ErrInvalidPublicKey},
+	}
+	for _, tc := range cases {
+		_, err := VerifierFromECDSAPublicKey(tc.pub, tc.suiteType)
+		if !errors.Is(err, tc.err) {
+			t.Errorf("%s: Got %v, expected %v", tc.name, err, tc.err)
+		}
+	}
+}
+
+func TestSignerFromPEMFile(t *testing.T) {
+	dir := t.TempDir()
+	privFile := filepath.Join(dir, "key.pem")
+	pubFile := filepath.Join(dir, "key.pub.pem")
+	priv, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err := PrivateKeyToPEMFile(privFile, priv); err != nil {
+		t.Fatal(err)
+	}
+	if err := PublicKeyToPEMFile(pubFile, &priv.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+
+	signer, err := SignerFromPEMFile(privFile, "ecdsa_P384_sha-384_rfc6979")
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err := VerifierFromPEMFile(pubFile, "ecdsa_P384_sha-384")
+	if err != nil {
+		t.Fatal(err)
+	}
+	message := []byte("message")
+	signature, err := signer.Sign(message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verifier.Verify(message, signature) {
+		t.Error("signature should verify")
+	}
+
+	if _, err := SignerFromPEMFile(privFile, "ecdsa_P256_sha-256_rfc6979"); !errors.Is(err, ErrUnexpectedSuite) {
+		t.Errorf("Got %v, expected %v", err, ErrUnexpectedSuite)
+	}
+	if _, err := VerifierFromPEMFile(pubFile, "ed25519"); !errors.Is(err, ErrUnexpectedSuite) {
+		t.Errorf("Got %v, expected %v", err, ErrUnexpectedSuite)
+	}
+	if _, err := SignerFromPEMFile(filepath.Join(dir, "missing.pem"), "ecdsa_P384_sha-384_rfc6979"); err == nil {
+		t.Error("missing file should not create a signer")
+	}
+}
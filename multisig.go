@@ -0,0 +1,199 @@
+package crypto
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// A multisig bundle holds the signatures of some of the members of an M of N
+// multisig. It is encoded as a 1 byte entry count followed by the entries in
+// increasing member index order, each a 1 byte member index, a 2 byte big
+// endian signature length and the signature:
+//
+//	count || index || length || signature || index || length || signature ...
+//
+// Ordering the entries makes the encoding canonical and rules out two
+// signatures from the same member.
+const (
+	// MultisigMaxMembers Maximum number of members of a multisig, indexes fit
+	// in a byte
+	MultisigMaxMembers = 255
+	// MultisigMaxSignatureLength Maximum length of a member signature in a
+	// bundle
+	MultisigMaxSignatureLength = 0xffff
+)
+
+// MultisigEntry is the signature of the multisig member at Index, the
+// position of its verifier in the list given to NewMultisigVerifier.
+type MultisigEntry struct {
+	Index     int
+	Signature []byte
+}
+
+// EncodeMultisigBundle encodes member signatures as a multisig bundle. The
+// entries may be in any order, an error is returned if two share an index.
+func EncodeMultisigBundle(entries []MultisigEntry) ([]byte, error) {
+	if len(entries) > MultisigMaxMembers {
+		return nil, fmt.Errorf(
+			"%w: at most %d entries, got %d",
+			ErrSignatureEncoding, MultisigMaxMembers, len(entries))
+	}
+	sorted := append([]MultisigEntry{}, entries...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Index < sorted[j].Index })
+
+	bundle := []byte{byte(len(sorted))}
+	for i, entry := range sorted {
+		if entry.Index < 0 || entry.Index >= MultisigMaxMembers {
+			return nil, fmt.Errorf("%w: member index %d out of range", ErrSignatureEncoding, entry.Index)
+		}
+		if i > 0 && sorted[i-1].Index == entry.Index {
+			return nil, fmt.Errorf("%w: duplicate member index %d", ErrSignatureEncoding, entry.Index)
+		}
+		if len(entry.Signature) > MultisigMaxSignatureLength {
+			return nil, fmt.Errorf(
+				"%w: signature of member %d is %d bytes",
+				ErrSignatureLength, entry.Index, len(entry.Signature))
+		}
+		bundle = append(bundle, byte(entry.Index))
+		bundle = binary.BigEndian.AppendUint16(bundle, uint16(len(entry.Signature)))
+		bundle = append(bundle, entry.Signature...)
+	}
+	return bundle, nil
+}
+
+// DecodeMultisigBundle decodes a multisig bundle. Bundles that are truncated,
+// have trailing data or entries out of index order are rejected.
+func DecodeMultisigBundle(bundle []byte) ([]MultisigEntry, error) {
+	if len(bundle) == 0 {
+		return nil, fmt.Errorf("%w: empty multisig bundle", ErrSignatureEncoding)
+	}
+	count := int(bundle[0])
+	rest := bundle[1:]
+	entries := make([]MultisigEntry, 0, count)
+	for i := 0; i < count; i++ {
+		if len(rest) < 3 {
+			return nil, fmt.Errorf("%w: truncated multisig bundle", ErrSignatureEncoding)
+		}
+		index := int(rest[0])
+		length := int(binary.BigEndian.Uint16(rest[1:3]))
+		rest = rest[3:]
+		if len(rest) < length {
+			return nil, fmt.Errorf("%w: truncated multisig bundle", ErrSignatureEncoding)
+		}
+		if i > 0 && entries[i-1].Index >= index {
+			return nil, fmt.Errorf("%w: member indexes not in increasing order", ErrSignatureEncoding)
+		}
+		entries = append(entries, MultisigEntry{Index: index, Signature: rest[:length]})
+		rest = rest[length:]
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%w: unexpected data after multisig bundle", ErrSignatureEncoding)
+	}
+	return entries, nil
+}
+
+// NewMultisigVerifier constructor for a Verifier of M of N multisig bundles.
+// Each member verifier may be of any suite. Verify returns true when the
+// bundle holds valid signatures over the message from at least threshold
+// different members. Members holding a public key, see PublicKeyHolder, must
+// all have different keys, an error is returned if a key is listed twice,
+// even under different suites. A secp256k1 key is identified by its x
+// coordinate, so a schnorr and an ECDSA member of the same key are rejected.
+// Members without a public key, such as Ethereum address verifiers, are not
+// checked, an address verifier and a secp256k1 member may be the same key.
+func NewMultisigVerifier(threshold int, members []Verifier) (Verifier, error) {
+	if len(members) == 0 || len(members) > MultisigMaxMembers {
+		return nil, errors.New(
+			"multisig should have 1 to " + fmt.Sprint(MultisigMaxMembers) +
+				" members got " + fmt.Sprint(len(members)))
+	}
+	if threshold < 1 || threshold > len(members) {
+		return nil, errors.New(
+			"threshold should be between 1 and " + fmt.Sprint(len(members)) +
+				" got " + fmt.Sprint(threshold))
+	}
+	keyIDs := make(map[string]int, len(members))
+	for i, member := range members {
+		if member == nil {
+			return nil, errors.New("multisig member " + fmt.Sprint(i) + " is nil")
+		}
+		keyID, ok := multisigMemberKeyID(member)
+		if !ok {
+			continue
+		}
+		if first, seen := keyIDs[keyID]; seen {
+			return nil, errors.New(
+				"multisig members " + fmt.Sprint(first) + " and " + fmt.Sprint(i) +
+					" have the same public key")
+		}
+		keyIDs[keyID] = i
+	}
+	return &multisigVerifier{
+		threshold: threshold,
+		members:   append([]Verifier{}, members...),
+		suiteType: "multisig",
+	}, nil
+}
+
+// Returns an ID of the member's key that does not depend on its suite, false
+// if the member does not hold a public key. BIP-340 keys are only the x
+// coordinate of the point, ECDSA secp256k1 keys are X || Y.
+func multisigMemberKeyID(member Verifier) (string, bool) {
+	switch m := member.(type) {
+	case *schnorrVerifier, *schnorrSigner:
+		return "secp256k1:" + KeyIDFromPublicKey(m.(PublicKeyHolder).PublicKey()), true
+	case *secp256k1Verifier, *secp256k1Signer:
+		return "secp256k1:" + KeyIDFromPublicKey(m.(PublicKeyHolder).PublicKey()[:32]), true
+	case PublicKeyHolder:
+		return m.KeyID(), true
+	}
+	return "", false
+}
+
+type multisigVerifier struct {
+	threshold int
+	members   []Verifier
+	suiteType string
+}
+
+func (s *multisigVerifier) SuiteType() string {
+	return s.suiteType
+}
+
+func (s *multisigVerifier) Verify(toVerify []byte, signature []byte) bool {
+	return s.VerifyErr(toVerify, signature) == nil
+}
+
+// VerifyErr verifies a bundle, returning why it failed, see ErrorVerifier.
+// When too few signatures are valid the error wraps ErrSignatureMismatch.
+func (s *multisigVerifier) VerifyErr(toVerify []byte, signature []byte) error {
+	entries, err := DecodeMultisigBundle(signature)
+	if err != nil {
+		return err
+	}
+	// Entries are in increasing index order, the last has the largest.
+	if len(entries) > 0 && entries[len(entries)-1].Index >= len(s.members) {
+		return fmt.Errorf(
+			"%w: no member with index %d",
+			ErrSignatureEncoding, entries[len(entries)-1].Index)
+	}
+	if len(entries) < s.threshold {
+		return fmt.Errorf(
+			"%w: %d signatures, threshold is %d",
+			ErrSignatureMismatch, len(entries), s.threshold)
+	}
+	valid := 0
+	for _, entry := range entries {
+		if s.members[entry.Index].Verify(toVerify, entry.Signature) {
+			valid++
+		}
+		if valid >= s.threshold {
+			return nil
+		}
+	}
+	return fmt.Errorf(
+		"%w: %d valid signatures, threshold is %d",
+		ErrSignatureMismatch, valid, s.threshold)
+}
@@ -0,0 +1,211 @@
+package crypto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Creates a 2 of 3 multisig of an ed25519, a secp256k1 and a P256 member.
+func newTestMultisig(t *testing.T) ([]Signer, Verifier) {
+	signers := make([]Signer, 0, 3)
+	members := make([]Verifier, 0, 3)
+	for _, tt := range []struct {
+		suiteType  string
+		privKeyHex string
+		pubKeyHex  string
+	}{
+		{"ed25519", Ed25519PrivHex, Ed25519PubHex},
+		{"ecdsa_secp256k1_keccak256", Secp256k1PrivHex, Secp256k1PubHex},
+		{"ecdsa_P256_sha-256_rfc6979", P256PrivHex, P256PubHex},
+	} {
+		privKey, _ := FromHex(tt.privKeyHex)
+		pubKey, _ := FromHex(tt.pubKeyHex)
+		signer, err := NewSignerForSuite(tt.suiteType, privKey)
+		if err != nil {
+			t.Fatalf("Error creating the signer: %s", err)
+		}
+		verifier, err := NewVerifierForSuite(tt.suiteType, pubKey)
+		if err != nil {
+			t.Fatalf("Error creating the verifier: %s", err)
+		}
+		signers = append(signers, signer)
+		members = append(members, verifier)
+	}
+	verifier, err := NewMultisigVerifier(2, members)
+	if err != nil {
+		t.Fatalf("Error creating the multisig verifier: %s", err)
+	}
+	return signers, verifier
+}
+
+func TestMultisigVerify(t *testing.T) {
+	signers, verifier := newTestMultisig(t)
+	if verifier.SuiteType() != "multisig" {
+		t.Errorf("Got suite %s, expected multisig", verifier.SuiteType())
+	}
+	message := []byte{1, 2, 3}
+	signatures := make([][]byte, len(signers))
+	for i, signer := range signers {
+		signatures[i], _ = signer.Sign(message)
+	}
+	badSignature := append([]byte{}, signatures[1]...)
+	badSignature[0] ^= 1
+
+	cases := []struct {
+		name    string
+		entries []MultisigEntry
+		err     error
+	}{
+		{"all_members", []MultisigEntry{{0, signatures[0]}, {1, signatures[1]}, {2, signatures[2]}}, nil},
+		{"threshold", []MultisigEntry{{2, signatures[2]}, {0, signatures[0]}}, nil},
+		{"one_bad_of_three", []MultisigEntry{{0, signatures[0]}, {1, badSignature}, {2, signatures[2]}}, nil},
+		{"below_threshold", []MultisigEntry{{1, signatures[1]}}, ErrSignatureMismatch},
+		{"one_bad_of_two", []MultisigEntry{{0, signatures[0]}, {1, badSignature}}, ErrSignatureMismatch},
+		{"wrong_member", []MultisigEntry{{0, signatures[0]}, {1, signatures[2]}}, ErrSignatureMismatch},
+		{"unknown_member", []MultisigEntry{{0, signatures[0]}, {1, signatures[1]}, {3, signatures[2]}}, ErrSignatureEncoding},
+		{"empty", nil, ErrSignatureMismatch},
+	}
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle, err := EncodeMultisigBundle(tt.entries)
+			if err != nil {
+				t.Fatalf("Error encoding bundle: %s", err)
+			}
+			if verifier.Verify(message, bundle) != (tt.err == nil) {
+				t.Errorf("Expected Verify to return %t.", tt.err == nil)
+			}
+			err = verifier.(ErrorVerifier).VerifyErr(message, bundle)
+			if !errors.Is(err, tt.err) || (err == nil) != (tt.err == nil) {
+				t.Errorf("Got %v, expected %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestMultisigVerifyDuplicateMember(t *testing.T) {
+	signers, verifier := newTestMultisig(t)
+	message := []byte{1, 2, 3}
+	signature, _ := signers[0].Sign(message)
+
+	// Two signatures from member 0, encoded by hand as the encoder refuses to.
+	bundle := []byte{2}
+	for i := 0; i < 2; i++ {
+		bundle = append(bundle, 0, 0, byte(len(signature)))
+		bundle = append(bundle, signature...)
+	}
+	if verifier.Verify(message, bundle) {
+		t.Errorf("Expected signatures from one member to not reach the threshold.")
+	}
+}
+
+func TestMultisigBundleRoundTrip(t *testing.T) {
+	entries := []MultisigEntry{{7, []byte{1, 2}}, {2, nil}, {254, []byte{3}}}
+	bundle, err := EncodeMultisigBundle(entries)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	expected, _ := FromHex("03" + "020000" + "07000201" + "02" + "fe000103")
+	if !reflect.DeepEqual(bundle, expected) {
+		t.Errorf("Got %x, expected %x", bundle, expected)
+	}
+	decoded, err := DecodeMultisigBundle(bundle)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if len(decoded) != 3 || decoded[0].Index != 2 || decoded[1].Index != 7 || decoded[2].Index != 254 {
+		t.Fatalf("Got %v, expected entries sorted by index", decoded)
+	}
+	if !reflect.DeepEqual(decoded[1].Signature, []byte{1, 2}) {
+		t.Errorf("Got %x, expected 0102", decoded[1].Signature)
+	}
+}
+
+func TestEncodeMultisigBundleInvalid(t *testing.T) {
+	badEntries := []struct {
+		name    string
+		entries []MultisigEntry
+	}{
+		{"duplicate_index", []MultisigEntry{{1, []byte{1}}, {1, []byte{2}}}},
+		{"negative_index", []MultisigEntry{{-1, []byte{1}}}},
+		{"index_too_large", []MultisigEntry{{MultisigMaxMembers, []byte{1}}}},
+		{"signature_too_long", []MultisigEntry{{0, make([]byte, MultisigMaxSignatureLength+1)}}},
+	}
+	for _, tt := range badEntries {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := EncodeMultisigBundle(tt.entries); err == nil {
+				t.Errorf("Expected an error.")
+			}
+		})
+	}
+}
+
+func TestDecodeMultisigBundleInvalid(t *testing.T) {
+	badBundles := []struct {
+		name   string
+		bundle string
+	}{
+		{"empty", ""},
+		{"missing_entry", "01"},
+		{"truncated_header", "010000"},
+		{"truncated_signature", "0100000201"},
+		{"trailing_data", "00" + "00"},
+		{"duplicate_index", "02" + "01000101" + "01000102"},
+		{"decreasing_index", "02" + "02000101" + "01000102"},
+	}
+	for _, tt := range badBundles {
+		t.Run(tt.name, func(t *testing.T) {
+			bundle, _ := FromHex(tt.bundle)
+			_, err := DecodeMultisigBundle(bundle)
+			if !errors.Is(err, ErrSignatureEncoding) {
+				t.Errorf("Expected ErrSignatureEncoding, got %v", err)
+			}
+		})
+	}
+}
+
+func TestNewMultisigVerifierInvalid(t *testing.T) {
+	pubKey, _ := FromHex(Ed25519PubHex)
+	member, _ := NewEd25519Verifier(pubKey)
+	sameKey, _ := NewEd25519Verifier(pubKey)
+	privKey, _ := FromHex(Ed25519PrivHex)
+	signer, _ := NewEd25519Signer(privKey)
+	p256PubKey, _ := FromHex(P256PubHex)
+	p256Member, _ := NewP256Sha_256Verifier(p256PubKey)
+	p256OtherSuite, _ := NewP256Sha3_256Verifier(p256PubKey)
+	k256PrivKey, _ := FromHex(Secp256k1PrivHex)
+	schnorrMember, _ := NewSecp256k1SchnorrSigner(k256PrivKey)
+	ecdsaMember, _ := NewSecp256k1Sha_256Signer(k256PrivKey)
+	keccakPubKey, _ := FromHex(Secp256k1PubHex)
+	keccakMember, _ := NewSecp256k1Keccak256Verifier(keccakPubKey)
+	schnorrPubMember, _ := NewSecp256k1SchnorrVerifier(schnorrMember.PublicKey())
+	tooMany := make([]Verifier, MultisigMaxMembers+1)
+	for i := range tooMany {
+		tooMany[i] = member
+	}
+
+	badConfigs := []struct {
+		name      string
+		threshold int
+		members   []Verifier
+	}{
+		{"no_members", 1, nil},
+		{"zero_threshold", 0, []Verifier{member}},
+		{"threshold_above_members", 2, []Verifier{member}},
+		{"nil_member", 1, []Verifier{member, nil}},
+		{"too_many_members", 1, tooMany},
+		{"repeated_member", 2, []Verifier{member, member}},
+		{"repeated_key", 1, []Verifier{member, p256Member, sameKey}},
+		{"signer_and_verifier", 2, []Verifier{signer, member}},
+		{"key_in_two_suites", 2, []Verifier{p256Member, p256OtherSuite}},
+		{"schnorr_and_ecdsa_signers", 2, []Verifier{schnorrMember, ecdsaMember}},
+		{"schnorr_and_ecdsa_verifiers", 2, []Verifier{keccakMember, schnorrPubMember}},
+	}
+	for _, tt := range badConfigs {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewMultisigVerifier(tt.threshold, tt.members); err == nil {
+				t.Errorf("Expected an error.")
+			}
+		})
+	}
+}
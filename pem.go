@@ -2,6 +2,7 @@ package crypto
 
 import (
 	"crypto/ecdsa"
+	"crypto/rsa"
 	"encoding/pem"
 	"errors"
 	"io/ioutil"
@@ -101,3 +102,75 @@ func PublicKeyToPEMFile(fileName string, publicKey *ecdsa.PublicKey) error {
 	}
 	return nil
 }
+
+// EncodeRSAPrivateKeyPEM returns the PKCS#1 DER-encoded PEM encoding of an RSA
+// private key, in a "RSA PRIVATE KEY" block
+func EncodeRSAPrivateKeyPEM(prv *rsa.PrivateKey) ([]byte, error) {
+	pkcs1encoding, err := MarshalRSAPrivateKeyPKCS1(prv)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: pkcs1encoding}), nil
+}
+
+// EncodeRSAPublicKeyPEM returns the PKIX DER-encoded PEM encoding of an RSA
+// public key, in a "PUBLIC KEY" block
+func EncodeRSAPublicKeyPEM(pubk *rsa.PublicKey) ([]byte, error) {
+	x509encoding, err := MarshalRSAPublicKeyPKIX(pubk)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: x509encoding}), nil
+}
+
+// RSAPrivateKeyFromPEMFile reads a PEM encoded RSA private key from file, the
+// key may be in PKCS#1 ("RSA PRIVATE KEY") or PKCS#8 ("PRIVATE KEY") format
+func RSAPrivateKeyFromPEMFile(fileName string) (*rsa.PrivateKey, error) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	der, err := DecodeX509PEM(content)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalRSAPrivateKey(der)
+}
+
+// RSAPublicKeyFromPEMFile reads a PEM encoded RSA public key from file, the key
+// may be in PKIX ("PUBLIC KEY") or PKCS#1 ("RSA PUBLIC KEY") format
+func RSAPublicKeyFromPEMFile(fileName string) (*rsa.PublicKey, error) {
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+	der, err := DecodeX509PEM(content)
+	if err != nil {
+		return nil, err
+	}
+	return UnmarshalRSAPublicKey(der)
+}
+
+// RSAPrivateKeyToPEMFile writes a PKCS#1 PEM encoded RSA private key to a file
+func RSAPrivateKeyToPEMFile(fileName string, privateKey *rsa.PrivateKey) error {
+	content, err := EncodeRSAPrivateKeyPEM(privateKey)
+	if err != nil {
+		return err
+	}
+	if content == nil {
+		return ErrPEMContentEmpty
+	}
+	return ioutil.WriteFile(fileName, content, 0600)
+}
+
+// RSAPublicKeyToPEMFile writes a PKIX PEM encoded RSA public key to a file
+func RSAPublicKeyToPEMFile(fileName string, publicKey *rsa.PublicKey) error {
+	content, err := EncodeRSAPublicKeyPEM(publicKey)
+	if err != nil {
+		return err
+	}
+	if content == nil {
+		return ErrPEMContentEmpty
+	}
+	return ioutil.WriteFile(fileName, content, 0600)
+}
@@ -0,0 +1,213 @@
+package crypto
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// SignerConstructor builds a Signer from private key data. All of the
+// NewXxxSigner functions in this package, e.g. NewEd25519Signer, satisfy it.
+type SignerConstructor func(privData []byte) (Signer, error)
+
+// VerifierConstructor builds a Verifier from public key data. All of the
+// NewXxxVerifier functions in this package, e.g. NewEd25519Verifier, satisfy
+// it.
+type VerifierConstructor func(pubData []byte) (Verifier, error)
+
+// PublicKeyRecoverer recovers the public key that created a signature over
+// message, for suites with recoverable signatures such as
+// ecdsa_secp256k1_keccak256.
+type PublicKeyRecoverer func(message []byte, signature []byte) ([]byte, error)
+
+// The registry maps the string returned by Suite.SuiteType() back to the
+// constructors that build signers and verifiers of that type. Every suite in
+// this package registers itself on init, third parties can add their own
+// with RegisterSuite.
+type suiteEntry struct {
+	newSigner   SignerConstructor
+	newVerifier VerifierConstructor
+	recoverer   PublicKeyRecoverer
+	// ecdsa is set for the ECDSA suites of this package, whose verifiers can
+	// be made to reject high S signatures, lowS when they are, see
+	// SetRequireLowS.
+	ecdsa bool
+	lowS  bool
+}
+
+var (
+	suitesMu sync.RWMutex
+	suites   = make(map[string]suiteEntry)
+)
+
+// RegisterSuite makes a suite available to NewSignerForSuite and
+// NewVerifierForSuite under the name suiteType. newSigner may be nil for
+// suites that can only verify, newVerifier is required. An error is returned
+// if the suite type is empty or already registered.
+func RegisterSuite(
+	suiteType string,
+	newSigner SignerConstructor,
+	newVerifier VerifierConstructor,
+) error {
+	if suiteType == "" {
+		return fmt.Errorf("%w: suite type must not be empty", ErrInvalidSuite)
+	}
+	if newVerifier == nil {
+		return fmt.Errorf("%w: %s has no verifier constructor", ErrInvalidSuite, suiteType)
+	}
+	suitesMu.Lock()
+	defer suitesMu.Unlock()
+	if _, ok := suites[suiteType]; ok {
+		return fmt.Errorf("%w: %s", ErrSuiteRegistered, suiteType)
+	}
+	suites[suiteType] = suiteEntry{
+		newSigner:   newSigner,
+		newVerifier: newVerifier,
+	}
+	return nil
+}
+
+// Used by the suites in this package to register themselves on init, a
+// failure here is a programming error.
+func mustRegisterSuite(
+	suiteType string,
+	newSigner SignerConstructor,
+	newVerifier VerifierConstructor,
+) {
+	if err := RegisterSuite(suiteType, newSigner, newVerifier); err != nil {
+		panic(err)
+	}
+}
+
+// Registers an ECDSA suite of this package, see SetRequireLowS.
+func mustRegisterECDSASuite(
+	suiteType string,
+	newSigner SignerConstructor,
+	newVerifier VerifierConstructor,
+) {
+	mustRegisterSuite(suiteType, newSigner, newVerifier)
+	suitesMu.Lock()
+	defer suitesMu.Unlock()
+	entry := suites[suiteType]
+	entry.ecdsa = true
+	suites[suiteType] = entry
+}
+
+// SetRequireLowS sets whether the verifiers of an ECDSA suite built by
+// NewVerifierForSuite, and so by VerifyEnvelope, reject signatures with a
+// high S as NewLowSVerifier does. The Verify method of signers built by
+// NewSignerForSuite rejects them as well. Use it when signatures are compared
+// or deduplicated as bytes, e.g.
+//
+//	err := SetRequireLowS("ecdsa_P256_sha-256_rfc6979", true)
+//
+// Returns ErrUnknownSuite for unregistered suites and ErrUnexpectedSuite for
+// suites other than the ECDSA suites of this package.
+func SetRequireLowS(suiteType string, required bool) error {
+	suitesMu.Lock()
+	defer suitesMu.Unlock()
+	entry, ok := suites[suiteType]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSuite, suiteType)
+	}
+	if !entry.ecdsa {
+		return fmt.Errorf("%w: %s is not an ECDSA suite", ErrUnexpectedSuite, suiteType)
+	}
+	entry.lowS = required
+	suites[suiteType] = entry
+	return nil
+}
+
+// RegisterRecoverer adds public key recovery to an already registered suite,
+// making it available to RecoverPublicKey.
+func RegisterRecoverer(suiteType string, recoverer PublicKeyRecoverer) error {
+	if recoverer == nil {
+		return fmt.Errorf("%w: %s has no recoverer", ErrInvalidSuite, suiteType)
+	}
+	suitesMu.Lock()
+	defer suitesMu.Unlock()
+	entry, ok := suites[suiteType]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownSuite, suiteType)
+	}
+	if entry.recoverer != nil {
+		return fmt.Errorf("%w: %s already has a recoverer", ErrSuiteRegistered, suiteType)
+	}
+	entry.recoverer = recoverer
+	suites[suiteType] = entry
+	return nil
+}
+
+func mustRegisterRecoverer(suiteType string, recoverer PublicKeyRecoverer) {
+	if err := RegisterRecoverer(suiteType, recoverer); err != nil {
+		panic(err)
+	}
+}
+
+func lookupSuite(suiteType string) (suiteEntry, error) {
+	suitesMu.RLock()
+	defer suitesMu.RUnlock()
+	entry, ok := suites[suiteType]
+	if !ok {
+		return suiteEntry{}, fmt.Errorf("%w: %s", ErrUnknownSuite, suiteType)
+	}
+	return entry, nil
+}
+
+// NewSignerForSuite constructs a Signer of the given suite type from private
+// key data, e.g. NewSignerForSuite("ed25519", privKey).
+func NewSignerForSuite(suiteType string, privData []byte) (Signer, error) {
+	entry, err := lookupSuite(suiteType)
+	if err != nil {
+		return nil, err
+	}
+	if entry.newSigner == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSuiteCannotSign, suiteType)
+	}
+	signer, err := entry.newSigner(privData)
+	if err != nil || !entry.lowS {
+		return signer, err
+	}
+	return newLowSSigner(signer)
+}
+
+// NewVerifierForSuite constructs a Verifier of the given suite type from public
+// key data. The suite type of a signer, e.g. ecdsa_P256_sha3-256_det, can be
+// used to build the verifier for its signatures.
+func NewVerifierForSuite(suiteType string, pubData []byte) (Verifier, error) {
+	entry, err := lookupSuite(suiteType)
+	if err != nil {
+		return nil, err
+	}
+	verifier, err := entry.newVerifier(pubData)
+	if err != nil || !entry.lowS {
+		return verifier, err
+	}
+	return NewLowSVerifier(verifier)
+}
+
+// RecoverPublicKey returns the public key that created signature over message
+// using the given suite type. The key is in the same format the suite's
+// verifier constructor takes, so it can be passed to NewVerifierForSuite.
+func RecoverPublicKey(suiteType string, message []byte, signature []byte) ([]byte, error) {
+	entry, err := lookupSuite(suiteType)
+	if err != nil {
+		return nil, err
+	}
+	if entry.recoverer == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSuiteCannotRecover, suiteType)
+	}
+	return entry.recoverer(message, signature)
+}
+
+// ListSuites returns the sorted names of all registered suites.
+func ListSuites() []string {
+	suitesMu.RLock()
+	defer suitesMu.RUnlock()
+	names := make([]string, 0, len(suites))
+	for name := range suites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,179 @@
+package crypto
+
+import (
+	"errors"
+	"sort"
+	"testing"
+)
+
+var registryTestCases = []struct {
+	suiteType  string
+	privKeyHex string
+	pubKeyHex  string
+}{
+	{"ecdsa_P256_sha3-256_det", P256PrivHex, P256PubHex},
+	{"ecdsa_P256_sha3-256_indet", P256PrivHex, P256PubHex},
+	{"ecdsa_P256_sha3-256_rfc6979", P256PrivHex, P256PubHex},
+	{"ecdsa_P256_sha-256_rfc6979", P256PrivHex, P256PubHex},
+	{"ecdsa_P256_sha-256_indet", P256PrivHex, P256PubHex},
+	{"ecdsa_P256_shake256_det", P256PrivHex, P256PubHex},
+	{"ecdsa_P256_shake256_indet", P256PrivHex, P256PubHex},
+	{"ecdsa_P256_shake256_rfc6979", P256PrivHex, P256PubHex},
+	{"ecdsa_P384_sha-384_rfc6979", P384PrivHex, P384PubHex},
+	{"ecdsa_P384_sha-384_indet", P384PrivHex, P384PubHex},
+	{"ecdsa_P521_sha-512_rfc6979", P521PrivHex, P521PubHex},
+	{"ecdsa_P521_sha-512_indet", P521PrivHex, P521PubHex},
+	{"ecdsa_P521_sha3-512_rfc6979", P521PrivHex, P521PubHex},
+	{"ecdsa_P521_sha3-512_indet", P521PrivHex, P521PubHex},
+	{"ecdsa_secp256k1_keccak256", Secp256k1PrivHex, Secp256k1PubHex},
+	{"ecdsa_secp256k1_sha-256", Secp256k1PrivHex, Secp256k1PubHex},
+	{"ed25519", Ed25519PrivHex, Ed25519PubHex},
+	{"schnorr_secp256k1_bip340", bip340TestCases[1].secretKey, bip340TestCases[1].publicKey},
+	{"bls12381_minpk_pop", blsTestCases[0].privKey, blsTestCases[0].pubKey},
+	{"rsa_pss_sha-256", RSAPrivHex, RSAPubHex},
+	{"rsa_pss_sha-384", RSAPrivHex, RSAPubHex},
+	{"rsa_pkcs1v15_sha-256", RSAPrivHex, RSAPubHex},
+	{"ed25519ph", Ed25519PrivHex, Ed25519PubHex},
+	{"ed448", ed448TestCases[0].seed + ed448TestCases[0].pubKey, ed448TestCases[0].pubKey},
+}
+
+func TestRegistryBuiltinSuites(t *testing.T) {
+	for _, tt := range registryTestCases {
+		t.Run(tt.suiteType, func(t *testing.T) {
+			privKey, _ := FromHex(tt.privKeyHex)
+			pubKey, _ := FromHex(tt.pubKeyHex)
+			signer, err := NewSignerForSuite(tt.suiteType, privKey)
+			if err != nil {
+				t.Fatalf("Error creating the signer: %s", err)
+			}
+			if signer.SuiteType() != tt.suiteType {
+				t.Errorf("Got suite %s, expected %s", signer.SuiteType(), tt.suiteType)
+			}
+
+			verifier, err := NewVerifierForSuite(tt.suiteType, pubKey)
+			if err != nil {
+				t.Fatalf("Error creating the verifier: %s", err)
+			}
+
+			message := []byte{1, 2, 3}
+			signature, err := signer.Sign(message)
+			if err != nil {
+				t.Fatalf("Error signing: %s", err)
+			}
+			if !verifier.Verify(message, signature) {
+				t.Errorf("Verifier didn't verify signature for signer.")
+			}
+
+			// The verifier reports its own suite type, which must resolve to
+			// a verifier as well.
+			if _, err := NewVerifierForSuite(verifier.SuiteType(), pubKey); err != nil {
+				t.Errorf("Error creating verifier from %s: %s", verifier.SuiteType(), err)
+			}
+		})
+	}
+}
+
+func TestRegistryVerifyOnlySuite(t *testing.T) {
+	privKey, _ := FromHex(P256PrivHex)
+	_, err := NewSignerForSuite("ecdsa_P256_sha3-256", privKey)
+	if !errors.Is(err, ErrSuiteCannotSign) {
+		t.Errorf("Expected ErrSuiteCannotSign, got %v", err)
+	}
+}
+
+func TestRegistryUnknownSuite(t *testing.T) {
+	_, sigErr := NewSignerForSuite("unknown", nil)
+	_, verErr := NewVerifierForSuite("unknown", nil)
+
+	if !errors.Is(sigErr, ErrUnknownSuite) {
+		t.Errorf("Expected ErrUnknownSuite, got %v", sigErr)
+	}
+	if !errors.Is(verErr, ErrUnknownSuite) {
+		t.Errorf("Expected ErrUnknownSuite, got %v", verErr)
+	}
+}
+
+func TestRegistryBadKey(t *testing.T) {
+	_, err := NewVerifierForSuite("ed25519", []byte{1, 2, 3})
+	if err == nil {
+		t.Errorf("Did not get verifier error.")
+	}
+}
+
+func TestRegisterSuite(t *testing.T) {
+	mock := &MockSigner{Suite: "test_register_suite", SignSigRet: []byte{4}}
+	newSigner := func(privData []byte) (Signer, error) { return mock, nil }
+	newVerifier := func(pubData []byte) (Verifier, error) { return mock, nil }
+
+	if err := RegisterSuite("test_register_suite", newSigner, newVerifier); err != nil {
+		t.Fatalf("Unexpected error registering suite: %s", err)
+	}
+	defer func() {
+		suitesMu.Lock()
+		delete(suites, "test_register_suite")
+		suitesMu.Unlock()
+	}()
+
+	err := RegisterSuite("test_register_suite", newSigner, newVerifier)
+	if !errors.Is(err, ErrSuiteRegistered) {
+		t.Errorf("Expected ErrSuiteRegistered, got %v", err)
+	}
+
+	signer, err := NewSignerForSuite("test_register_suite", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error creating signer: %s", err)
+	}
+	if signer != mock {
+		t.Errorf("Registered signer constructor was not used.")
+	}
+
+	found := false
+	for _, name := range ListSuites() {
+		if name == "test_register_suite" {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("Registered suite missing from ListSuites.")
+	}
+}
+
+func TestRegisterSuiteInvalid(t *testing.T) {
+	newVerifier := func(pubData []byte) (Verifier, error) { return nil, nil }
+	if err := RegisterSuite("", nil, newVerifier); !errors.Is(err, ErrInvalidSuite) {
+		t.Errorf("Expected ErrInvalidSuite for empty name, got %v", err)
+	}
+	if err := RegisterSuite("no_verifier", nil, nil); !errors.Is(err, ErrInvalidSuite) {
+		t.Errorf("Expected ErrInvalidSuite for missing verifier, got %v", err)
+	}
+}
+
+func TestListSuitesSorted(t *testing.T) {
+	names := ListSuites()
+	if !sort.StringsAreSorted(names) {
+		t.Errorf("Suites not sorted: %v", names)
+	}
+	for _, tt := range registryTestCases {
+		i := sort.SearchStrings(names, tt.suiteType)
+		if i == len(names) || names[i] != tt.suiteType {
+			t.Errorf("Suite %s missing from ListSuites.", tt.suiteType)
+		}
+	}
+}
+
+func TestRegisterRecoverer(t *testing.T) {
+	recoverer := func(message []byte, signature []byte) ([]byte, error) { return nil, nil }
+
+	err := RegisterRecoverer("unknown", recoverer)
+	if !errors.Is(err, ErrUnknownSuite) {
+		t.Errorf("Expected ErrUnknownSuite, got %v", err)
+	}
+	err = RegisterRecoverer("ecdsa_secp256k1_keccak256", recoverer)
+	if !errors.Is(err, ErrSuiteRegistered) {
+		t.Errorf("Expected ErrSuiteRegistered, got %v", err)
+	}
+	err = RegisterRecoverer("ed25519", nil)
+	if !errors.Is(err, ErrInvalidSuite) {
+		t.Errorf("Expected ErrInvalidSuite, got %v", err)
+	}
+}
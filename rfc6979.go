@@ -0,0 +1,137 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"hash"
+	"math/big"
+)
+
+// RFC 6979 describes a standard way of picking a deterministic k for ecdsa
+// signatures. Like the deterministicReader in ecdsa_reader.go, k is derived
+// from the private key and the message hash, but it is done with an HMAC_DRBG
+// so that any other RFC 6979 implementation produces the exact same
+// signatures. The HMAC is keyed with the same hash function used to hash the
+// message, as the RFC suggests.
+
+// rfc6979Nonces generates the sequence of candidate k values from section
+// 3.2 of RFC 6979. Candidates are tried in order until one produces a valid
+// signature.
+type rfc6979Nonces struct {
+	q    *big.Int
+	qlen int
+	hash func() hash.Hash
+	k    []byte
+	v    []byte
+	// first is false once a candidate has been rejected, step h.3 of the
+	// RFC must then run before the next candidate is generated.
+	first bool
+}
+
+func newRFC6979Nonces(
+	q *big.Int,
+	x *big.Int,
+	messageHash []byte,
+	newHash func() hash.Hash,
+) *rfc6979Nonces {
+	n := &rfc6979Nonces{
+		q:     q,
+		qlen:  q.BitLen(),
+		hash:  newHash,
+		first: true,
+	}
+	hlen := newHash().Size()
+	n.v = make([]byte, hlen)
+	for i := range n.v {
+		n.v[i] = 0x01
+	}
+	n.k = make([]byte, hlen)
+
+	privOctets := n.int2octets(x)
+	hashOctets := n.bits2octets(messageHash)
+	n.k = n.mac(n.k, n.v, []byte{0x00}, privOctets, hashOctets)
+	n.v = n.mac(n.k, n.v)
+	n.k = n.mac(n.k, n.v, []byte{0x01}, privOctets, hashOctets)
+	n.v = n.mac(n.k, n.v)
+	return n
+}
+
+func (n *rfc6979Nonces) mac(key []byte, data ...[]byte) []byte {
+	return hashBytes(hmac.New(n.hash, key), data...)
+}
+
+// Converts a bit string into an integer, keeping only the leftmost qlen bits.
+func (n *rfc6979Nonces) bits2int(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if blen := len(b) * 8; blen > n.qlen {
+		v.Rsh(v, uint(blen-n.qlen))
+	}
+	return v
+}
+
+// Converts an integer into a big-endian byte string the byte length of q.
+func (n *rfc6979Nonces) int2octets(v *big.Int) []byte {
+	out := make([]byte, (n.qlen+7)/8)
+	b := v.Bytes()
+	copy(out[len(out)-len(b):], b)
+	return out
+}
+
+func (n *rfc6979Nonces) bits2octets(b []byte) []byte {
+	z := n.bits2int(b)
+	if z.Cmp(n.q) >= 0 {
+		z.Sub(z, n.q)
+	}
+	return n.int2octets(z)
+}
+
+// next returns the next candidate k in the range [1, q-1].
+func (n *rfc6979Nonces) next() *big.Int {
+	for {
+		if !n.first {
+			n.k = n.mac(n.k, n.v, []byte{0x00})
+			n.v = n.mac(n.k, n.v)
+		}
+		n.first = false
+
+		var t []byte
+		for len(t)*8 < n.qlen {
+			n.v = n.mac(n.k, n.v)
+			t = append(t, n.v...)
+		}
+		k := n.bits2int(t)
+		if k.Sign() > 0 && k.Cmp(n.q) < 0 {
+			return k
+		}
+	}
+}
+
+// signRFC6979 creates an ecdsa signature for messageHash with k chosen as
+// described in RFC 6979.
+func signRFC6979(
+	privKey *ecdsa.PrivateKey,
+	messageHash []byte,
+	newHash func() hash.Hash,
+) (*big.Int, *big.Int) {
+	curve := privKey.Curve
+	q := curve.Params().N
+	nonces := newRFC6979Nonces(q, privKey.D, messageHash, newHash)
+	e := nonces.bits2int(messageHash)
+	for {
+		k := nonces.next()
+		r, _ := curve.ScalarBaseMult(nonces.int2octets(k))
+		r.Mod(r, q)
+		if r.Sign() == 0 {
+			continue
+		}
+		// s = k^-1 * (e + r * d) mod q
+		s := new(big.Int).Mul(r, privKey.D)
+		s.Add(s, e)
+		s.Mul(s, new(big.Int).ModInverse(k, q))
+		s.Mod(s, q)
+		if s.Sign() == 0 {
+			continue
+		}
+		return r, s
+	}
+}
@@ -0,0 +1,98 @@
+package crypto
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/sha256"
+	"crypto/sha512"
+	"hash"
+	"math/big"
+	"testing"
+)
+
+// Test vectors from RFC 6979 appendix A.2.5, ECDSA with the P-256 curve.
+const rfc6979P256PrivHex = "c9afa9d845ba75166b5c215767b1d6934e50c3db36e89b127b8a622b120f6721"
+
+var rfc6979TestCases = []struct {
+	name    string
+	newHash func() hash.Hash
+	message string
+	k       string
+	r       string
+	s       string
+}{
+	{
+		"sha256_sample", sha256.New, "sample",
+		"a6e3c57dd01abe90086538398355dd4c3b17aa873382b0f24d6129493d8aad60",
+		"efd48b2aacb6a8fd1140dd9cd45e81d69d2c877b56aaf991c34d0ea84eaf3716",
+		"f7cb1c942d657c41d436c7a1b6e29f65f3e900dbb9aff4064dc4ab2f843acda8",
+	},
+	{
+		"sha256_test", sha256.New, "test",
+		"d16b6ae827f17175e040871a1c7ec3500192c4c92677336ec2537acaee0008e0",
+		"f1abb023518351cd71d881567b1ea663ed3efcf6c5132b354f28d3b0b7d38367",
+		"019f4113742a2b14bd25926b49c649155f267e60d3814b4c0cc84250e46f0083",
+	},
+	{
+		"sha512_sample", sha512.New, "sample",
+		"5fa81c63109badb88c1f367b47da606da28cad69aa22c4fe6ad7df73a7173aa5",
+		"8496a60b5e9b47c825488827e0495b0e3fa109ec4568fd3f8d1097678eb97f00",
+		"2362ab1adbe2b8adf9cb9edab740ea6049c028114f2460f96554f61fae3302fe",
+	},
+}
+
+func rfc6979TestKey() *ecdsa.PrivateKey {
+	d, _ := FromHex(rfc6979P256PrivHex)
+	privKey := new(ecdsa.PrivateKey)
+	privKey.Curve = elliptic.P256()
+	privKey.D = new(big.Int).SetBytes(d)
+	privKey.X, privKey.Y = privKey.Curve.ScalarBaseMult(d)
+	return privKey
+}
+
+func TestRFC6979Nonce(t *testing.T) {
+	privKey := rfc6979TestKey()
+	for _, tt := range rfc6979TestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.newHash()
+			h.Write([]byte(tt.message))
+			nonces := newRFC6979Nonces(privKey.Params().N, privKey.D, h.Sum(nil), tt.newHash)
+			k := ToHex(nonces.int2octets(nonces.next()))
+			if k != tt.k {
+				t.Errorf("Got k %s, expected %s", k, tt.k)
+			}
+		})
+	}
+}
+
+func TestRFC6979Sign(t *testing.T) {
+	privKey := rfc6979TestKey()
+	for _, tt := range rfc6979TestCases {
+		t.Run(tt.name, func(t *testing.T) {
+			h := tt.newHash()
+			h.Write([]byte(tt.message))
+			digest := h.Sum(nil)
+			r, s := signRFC6979(privKey, digest, tt.newHash)
+			if ToHex(r.Bytes()) != tt.r {
+				t.Errorf("Got r %x, expected %s", r, tt.r)
+			}
+			if ToHex(s.Bytes()) != tt.s {
+				t.Errorf("Got s %x, expected %s", s, tt.s)
+			}
+			if !ecdsa.Verify(&privKey.PublicKey, digest, r, s) {
+				t.Errorf("Expected signature to verify, it did not.")
+			}
+		})
+	}
+}
+
+func TestRFC6979NonceRetry(t *testing.T) {
+	privKey := rfc6979TestKey()
+	digest := (&Sha_256Hasher{}).Hash([]byte("sample"))
+	nonces := newRFC6979Nonces(privKey.Params().N, privKey.D, digest, sha256.New)
+	first := nonces.next()
+	second := nonces.next()
+	if first.Cmp(second) == 0 {
+		t.Errorf("Expected a new candidate k after a rejection.")
+	}
+}
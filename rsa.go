@@ -0,0 +1,258 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"hash"
+)
+
+// RSA suites matching the JOSE algorithms PS256, PS384 and RS256. Private key
+// data is a DER encoded PKCS#1 or PKCS#8 RSA private key, public key data is a
+// DER encoded PKIX or PKCS#1 RSA public key, see MarshalRSAPrivateKeyPKCS1 and
+// MarshalRSAPublicKeyPKIX. The PSS suites use a salt as long as the hash, as
+// required by RFC 7518.
+const (
+	// RSAMinimumKeyBits Smallest modulus accepted by the RSA suites
+	RSAMinimumKeyBits = 2048
+	// RSADefaultKeyBits Modulus size used by GenerateRSAKeyPair
+	RSADefaultKeyBits = 3072
+)
+
+func init() {
+	mustRegisterSuite("rsa_pss_sha-256", NewRSAPSSSha_256Signer, NewRSAPSSSha_256Verifier)
+	mustRegisterSuite("rsa_pss_sha-384", NewRSAPSSSha_384Signer, NewRSAPSSSha_384Verifier)
+	mustRegisterSuite("rsa_pkcs1v15_sha-256", NewRSAPKCS1v15Sha_256Signer, NewRSAPKCS1v15Sha_256Verifier)
+}
+
+// GenerateRSAKeyPair create a private/public RSA key pair with a modulus of
+// bits bits. Use RSADefaultKeyBits if there is no reason to pick a size.
+func GenerateRSAKeyPair(bits int) (*rsa.PrivateKey, *rsa.PublicKey, error) {
+	if bits < RSAMinimumKeyBits {
+		return nil, nil, fmt.Errorf(
+			"RSA keys must be at least %d bits, got %d", RSAMinimumKeyBits, bits)
+	}
+	prvKey, err := rsa.GenerateKey(rand.Reader, bits)
+	if err != nil {
+		return nil, nil, err
+	}
+	return prvKey, &prvKey.PublicKey, nil
+}
+
+// NewRSAPSSSha_256Verifier constructor for an RSA-PSS Verifier hashing with
+// SHA-256 (PS256)
+func NewRSAPSSSha_256Verifier(pubData []byte) (Verifier, error) {
+	return newRSAVerifier(pubData, &Sha_256Hasher{}, crypto.SHA256, true, "rsa_pss_sha-256")
+}
+
+// NewRSAPSSSha_256Signer constructor for an RSA-PSS Signer hashing with
+// SHA-256 (PS256)
+func NewRSAPSSSha_256Signer(privData []byte) (Signer, error) {
+	return newRSASigner(privData, &Sha_256Hasher{}, crypto.SHA256, true, "rsa_pss_sha-256")
+}
+
+// NewRSAPSSSha_384Verifier constructor for an RSA-PSS Verifier hashing with
+// SHA-384 (PS384)
+func NewRSAPSSSha_384Verifier(pubData []byte) (Verifier, error) {
+	return newRSAVerifier(pubData, &Sha_384Hasher{}, crypto.SHA384, true, "rsa_pss_sha-384")
+}
+
+// NewRSAPSSSha_384Signer constructor for an RSA-PSS Signer hashing with
+// SHA-384 (PS384)
+func NewRSAPSSSha_384Signer(privData []byte) (Signer, error) {
+	return newRSASigner(privData, &Sha_384Hasher{}, crypto.SHA384, true, "rsa_pss_sha-384")
+}
+
+// NewRSAPKCS1v15Sha_256Verifier constructor for an RSA PKCS#1 v1.5 Verifier
+// hashing with SHA-256 (RS256)
+func NewRSAPKCS1v15Sha_256Verifier(pubData []byte) (Verifier, error) {
+	return newRSAVerifier(pubData, &Sha_256Hasher{}, crypto.SHA256, false, "rsa_pkcs1v15_sha-256")
+}
+
+// NewRSAPKCS1v15Sha_256Signer constructor for an RSA PKCS#1 v1.5 Signer
+// hashing with SHA-256 (RS256)
+func NewRSAPKCS1v15Sha_256Signer(privData []byte) (Signer, error) {
+	return newRSASigner(privData, &Sha_256Hasher{}, crypto.SHA256, false, "rsa_pkcs1v15_sha-256")
+}
+
+func checkRSAKeySize(pubKey *rsa.PublicKey) error {
+	if pubKey.N.BitLen() < RSAMinimumKeyBits {
+		return fmt.Errorf(
+			"RSA keys must be at least %d bits, got %d", RSAMinimumKeyBits, pubKey.N.BitLen())
+	}
+	return nil
+}
+
+// Convenience function for creating verifiers. Used in functions such as
+// NewRSAPSSSha_256Verifier
+func newRSAVerifier(
+	pubData []byte,
+	hasher Hasher,
+	hash crypto.Hash,
+	pss bool,
+	suiteType string,
+) (Verifier, error) {
+	pubKey, err := UnmarshalRSAPublicKey(pubData)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+	}
+	if err := checkRSAKeySize(pubKey); err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrInvalidPublicKey, err)
+	}
+	return &rsaVerifier{
+		publicKey: pubKey,
+		hasher:    hasher,
+		hash:      hash,
+		pss:       pss,
+		suiteType: suiteType,
+	}, nil
+}
+
+// Convenience function for creating signers. Used in functions such as
+// NewRSAPSSSha_256Signer
+func newRSASigner(
+	privData []byte,
+	hasher Hasher,
+	hash crypto.Hash,
+	pss bool,
+	suiteType string,
+) (Signer, error) {
+	privKey, err := UnmarshalRSAPrivateKey(privData)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkRSAKeySize(&privKey.PublicKey); err != nil {
+		return nil, err
+	}
+	return &rsaSigner{
+		privateKey: privKey,
+		verifier: &rsaVerifier{
+			publicKey: &privKey.PublicKey,
+			hasher:    hasher,
+			hash:      hash,
+			pss:       pss,
+			suiteType: suiteType,
+		},
+	}, nil
+}
+
+// rsaVerifier Verifies RSA-PSS or RSA PKCS#1 v1.5 signatures
+type rsaVerifier struct {
+	publicKey *rsa.PublicKey
+	hasher    Hasher
+	hash      crypto.Hash
+	pss       bool
+	suiteType string
+}
+
+func (s *rsaVerifier) SuiteType() string {
+	return s.suiteType
+}
+
+// PublicKey returns the public key, PKIX DER, see PublicKeyHolder.
+func (s *rsaVerifier) PublicKey() []byte {
+	pubKey, _ := MarshalRSAPublicKeyPKIX(s.publicKey)
+	return pubKey
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *rsaVerifier) KeyID() string {
+	return KeyIDFromPublicKey(s.PublicKey())
+}
+
+func (s *rsaVerifier) Verify(toVerify []byte, signature []byte) bool {
+	return s.VerifyErr(toVerify, signature) == nil
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *rsaVerifier) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifyDigest(s.hasher.Hash(toVerify), signature)
+}
+
+func (s *rsaVerifier) newHash() hash.Hash {
+	hashFunc, _ := newStreamHash(s.hasher)
+	return hashFunc
+}
+
+// VerifyDigest verifies a signature over a digest, see DigestVerifier.
+func (s *rsaVerifier) VerifyDigest(digest []byte, signature []byte) bool {
+	return verifyCheckedDigest(s, digest, signature)
+}
+
+func (s *rsaVerifier) verifyDigest(digest []byte, signature []byte) error {
+	if len(signature) != s.publicKey.Size() {
+		return fmt.Errorf(
+			"%w: expected %d bytes, got %d bytes",
+			ErrSignatureLength, s.publicKey.Size(), len(signature))
+	}
+	var err error
+	if s.pss {
+		opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+		err = rsa.VerifyPSS(s.publicKey, s.hash, digest, signature, opts)
+	} else {
+		err = rsa.VerifyPKCS1v15(s.publicKey, s.hash, digest, signature)
+	}
+	if err != nil {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+type rsaSigner struct {
+	privateKey *rsa.PrivateKey
+	verifier   *rsaVerifier
+}
+
+func (s *rsaSigner) Sign(toSign []byte) ([]byte, error) {
+	return s.signDigest(s.verifier.hasher.Hash(toSign))
+}
+
+func (s *rsaSigner) signDigest(digest []byte) ([]byte, error) {
+	if s.verifier.pss {
+		opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash}
+		return rsa.SignPSS(rand.Reader, s.privateKey, s.verifier.hash, digest, opts)
+	}
+	return rsa.SignPKCS1v15(nil, s.privateKey, s.verifier.hash, digest)
+}
+
+func (s *rsaSigner) Verify(toVerify []byte, signature []byte) bool {
+	return s.verifier.Verify(toVerify, signature)
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *rsaSigner) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifier.VerifyErr(toVerify, signature)
+}
+
+func (s *rsaSigner) verifyDigest(digest []byte, signature []byte) error {
+	return s.verifier.verifyDigest(digest, signature)
+}
+
+func (s *rsaSigner) newHash() hash.Hash {
+	return s.verifier.newHash()
+}
+
+// SignDigest signs a digest computed with the suite's hash, see DigestSigner.
+func (s *rsaSigner) SignDigest(digest []byte) ([]byte, error) {
+	return signCheckedDigest(s, digest)
+}
+
+// VerifyDigest verifies a signature over a digest, see DigestVerifier.
+func (s *rsaSigner) VerifyDigest(digest []byte, signature []byte) bool {
+	return verifyCheckedDigest(s, digest, signature)
+}
+
+func (s *rsaSigner) SuiteType() string {
+	return s.verifier.SuiteType()
+}
+
+// PublicKey returns the public key, see PublicKeyHolder.
+func (s *rsaSigner) PublicKey() []byte {
+	return s.verifier.PublicKey()
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *rsaSigner) KeyID() string {
+	return s.verifier.KeyID()
+}
@@ -0,0 +1,227 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// 2048 bit test key, the private key is PKCS#1 DER and the public key PKIX DER.
+const RSAPrivHex = "308204a40201000282010100b225a94422a23cda65ec32cd78a9b1e79d300ce1a7a0a6a63be38900c39c54ae536927455d2751ab8705f290e474f1c312f7bf16" +
+	"a8413609889e55e67215219366b50d8d6c7702d9016ae230ef731773e7a1de0eb82261b03c0a91869ab444b77522f9075ee0b10acf9dae10336cc88cf4624c55" +
+	"2c87d39804e757755b187803a56e47217b2bacaed6fb9bc28f5974812fd30e94f4777f5971779843cd97667906e2ea2371370d5f0cf73ebb2916212f949fabce" +
+	"46f76bdff1a8cfe9c8fece56af0a413db86a23178a4e2e41730ddab06319ce3e7d589934165b530dcae8e59437b3020b3ca3d437ddf08c55b69d6b20360f681d" +
+	"7fb9c377d7de266154b181f10203010001028201000149264c9897652b0a5b470e96955af459fd13cab923feafea74bcf3657606626fb5ad93e1953c10a9dbd0" +
+	"215d94be7df2f018cff80ce86fef2d7d5f39698fa179b74acebf7ea8659a8e03ede87c8329d1fd2816211c166a683487525a617cb17a45f3582b4621e0fb189f" +
+	"3dfcb1074d8522047d04e24213b1cd5da33a321603a5a2307c0d02fc66c2a3c2120675ac23fffb0349b10c2bdbcd54b8f910118da1fcba722688f4f5d3f79de3" +
+	"c2df05c1d85fd1f14ada3d226d15a0aee6071efec25b51f3e9af5aec6bc49838817cb018bf65347df232437c46b41d6ceb477d016e92b5b774672636c5f0c752" +
+	"fa2470659a217410adddce40ff0b33d92faa72a04502818100c81a2eacc6abd3137258d951ddfa25c4bed13bda8f1657518a6c9f33c468669fdc8cfce7969db8" +
+	"303fc2108174df2021215b8155df99ead9e36e18fd7a94cd2c1aadd9001c1e0768101f6900260df4aa49c81a99f9137eee808a91fe55a16aff8b4a0b24d2642e" +
+	"d0ed0568b3b4cf2a144ae4def9f92773929f99deddd41580ff02818100e3e968f7f4581df057e8318244354ee9d6c030852c9ffd49d45b737ef6fd5e45371735" +
+	"c4b9db7a911065afb53879a5849e64941ed05ec48ef4eade96c82d73b74162c69c2e195aa27abae4cdea2e35515ade56b86db5ed3f6b87ab9236e2500d4a4a91" +
+	"15b74f6234d980a65b6c22a9c24826ace5903f9c96a267ca364e1e0d0f0281804d77b5723647f723a435398d343ecfb867976170afcc9a697b09aa68c43516e5" +
+	"f4e0b058883aef3fdeaf26ecb76e5a65f9200fe64af6afb14317309d9183621b1d0908e5bdfcf2291b74cd95bd25d1fecb29a902cb26fd1120ba84e2ce0bfbfc" +
+	"8ad4539421cf9f404b42b8d239c1aa2cc901ea4124705d91fa2ffbd42d7355c9028181008ddd8a635d8b440789109550bad85be89eae3f058683880c5ecd1615" +
+	"5ff4d2ab4ee179ac9cec0b5d24d1c70f8cb9dbd7b3287432c20d5b58890771ca758c254d5abbfb58d8311c03ab558e7ac65c4100050a04fd03fd8c523fc2d17f" +
+	"4ffe8b49da8693cceaafd5378473da196a34216b37b22b493203069b9537f9dcd7bf48ed02818100a5d69f0ce1ae405e8bf88269d93aca12ce66b369eccd3933" +
+	"b1695ecc2d88317564ba2d74483a728100808d763933ba084b96122ac7506761654517a86ab4fb5a371f1adc6fef2db27807b87af393e1c4bba4fcc283467baf" +
+	"59c30559e386c0d07f3e0e92e6abe8bca9ead90c2e726ad3fd014a59b71e59d5a1df93026ce39704"
+const RSAPubHex = "30820122300d06092a864886f70d01010105000382010f003082010a0282010100b225a94422a23cda65ec32cd78a9b1e79d300ce1a7a0a6a63be38900c39c54" +
+	"ae536927455d2751ab8705f290e474f1c312f7bf16a8413609889e55e67215219366b50d8d6c7702d9016ae230ef731773e7a1de0eb82261b03c0a91869ab444" +
+	"b77522f9075ee0b10acf9dae10336cc88cf4624c552c87d39804e757755b187803a56e47217b2bacaed6fb9bc28f5974812fd30e94f4777f5971779843cd9766" +
+	"7906e2ea2371370d5f0cf73ebb2916212f949fabce46f76bdff1a8cfe9c8fece56af0a413db86a23178a4e2e41730ddab06319ce3e7d589934165b530dcae8e5" +
+	"9437b3020b3ca3d437ddf08c55b69d6b20360f681d7fb9c377d7de266154b181f10203010001"
+
+var rsaConstructorTestCases = []struct {
+	signerNew     func([]byte) (Signer, error)
+	verifierNew   func([]byte) (Verifier, error)
+	suiteType     string
+	deterministic bool
+}{
+	{NewRSAPSSSha_256Signer, NewRSAPSSSha_256Verifier, "rsa_pss_sha-256", false},
+	{NewRSAPSSSha_384Signer, NewRSAPSSSha_384Verifier, "rsa_pss_sha-384", false},
+	{NewRSAPKCS1v15Sha_256Signer, NewRSAPKCS1v15Sha_256Verifier, "rsa_pkcs1v15_sha-256", true},
+}
+
+func TestRSAConstructorPairSuccess(t *testing.T) {
+	privKey, _ := FromHex(RSAPrivHex)
+	pubKey, _ := FromHex(RSAPubHex)
+	for _, tt := range rsaConstructorTestCases {
+		t.Run(tt.suiteType, func(t *testing.T) {
+			signer, err := tt.signerNew(privKey)
+			if err != nil {
+				t.Fatalf("Error creating the signer: %s", err)
+			}
+			verifier, err := tt.verifierNew(pubKey)
+			if err != nil {
+				t.Fatalf("Error creating the verifier: %s", err)
+			}
+			if signer.SuiteType() != tt.suiteType || verifier.SuiteType() != tt.suiteType {
+				t.Errorf("Got %s, expected %s", signer.SuiteType(), tt.suiteType)
+			}
+
+			message := []byte{1, 2, 3}
+			signature1, err := signer.Sign(message)
+			if err != nil {
+				t.Fatalf("Got a sign error %s", err)
+			}
+			signature2, _ := signer.Sign(message)
+			if len(signature1) != 256 {
+				t.Errorf("Expected 256 byte signature, got %d", len(signature1))
+			}
+			if !verifier.Verify(message, signature1) || !signer.Verify(message, signature2) {
+				t.Errorf("Verifier didn't verify signature for signer.")
+			}
+			if verifier.Verify([]byte{1, 2}, signature1) {
+				t.Errorf("Expected signature over another message to not verify.")
+			}
+			if verifier.Verify(message, signature1[1:]) {
+				t.Errorf("Expected short signature to not verify.")
+			}
+			if reflect.DeepEqual(signature1, signature2) != tt.deterministic {
+				t.Errorf("Expected deterministic %t", tt.deterministic)
+			}
+		})
+	}
+}
+
+func TestRSASuitesDoNotCrossVerify(t *testing.T) {
+	privKey, _ := FromHex(RSAPrivHex)
+	pubKey, _ := FromHex(RSAPubHex)
+	pssSigner, _ := NewRSAPSSSha_256Signer(privKey)
+	pkcs1Verifier, _ := NewRSAPKCS1v15Sha_256Verifier(pubKey)
+	signature, _ := pssSigner.Sign([]byte{1, 2, 3})
+	if pkcs1Verifier.Verify([]byte{1, 2, 3}, signature) {
+		t.Errorf("Expected PSS signature to not verify as PKCS#1 v1.5.")
+	}
+}
+
+func TestRSAStdlibInterop(t *testing.T) {
+	privDER, _ := FromHex(RSAPrivHex)
+	pubKey, _ := FromHex(RSAPubHex)
+	priv, _ := UnmarshalRSAPrivateKey(privDER)
+	message := []byte("hello world")
+	digest := (&Sha_256Hasher{}).Hash(message)
+
+	pss, _ := rsa.SignPSS(rand.Reader, priv, crypto.SHA256, digest,
+		&rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash})
+	verifier, _ := NewRSAPSSSha_256Verifier(pubKey)
+	if !verifier.Verify(message, pss) {
+		t.Errorf("Expected standard library PSS signature to verify.")
+	}
+
+	signer, _ := NewRSAPKCS1v15Sha_256Signer(privDER)
+	signature, _ := signer.Sign(message)
+	if err := rsa.VerifyPKCS1v15(&priv.PublicKey, crypto.SHA256, digest, signature); err != nil {
+		t.Errorf("Expected standard library to verify signature: %s", err)
+	}
+}
+
+func TestRSAKeyErrors(t *testing.T) {
+	_, sigErr := NewRSAPSSSha_256Signer([]byte{1, 2, 3})
+	_, verErr := NewRSAPSSSha_256Verifier([]byte{1, 2, 3})
+	if sigErr == nil {
+		t.Errorf("Did not get signature error.")
+	}
+	if verErr == nil {
+		t.Errorf("Did not get verifier error.")
+	}
+
+	_, ecPub, _ := GenerateKeyPairP256()
+	ecDER, _ := MarshaPublicKeyX509(ecPub)
+	_, err := NewRSAPSSSha_256Verifier(ecDER)
+	if !errors.Is(err, ErrUnexpectedKeyType) {
+		t.Errorf("Expected ErrUnexpectedKeyType, got %v", err)
+	}
+}
+
+func TestGenerateRSAKeyPair(t *testing.T) {
+	_, _, err := GenerateRSAKeyPair(1024)
+	if err == nil {
+		t.Errorf("Expected error for a 1024 bit key.")
+	}
+
+	priv, pub, err := GenerateRSAKeyPair(RSAMinimumKeyBits)
+	if err != nil {
+		t.Fatalf("Got a key generation error %s", err)
+	}
+	privDER, _ := MarshalRSAPrivateKeyPKCS1(priv)
+	pubDER, _ := MarshalRSAPublicKeyPKIX(pub)
+	signer, err := NewRSAPSSSha_384Signer(privDER)
+	if err != nil {
+		t.Fatalf("Got a constructor error %s", err)
+	}
+	verifier, err := NewRSAPSSSha_384Verifier(pubDER)
+	if err != nil {
+		t.Fatalf("Got a constructor error %s", err)
+	}
+	signature, _ := signer.Sign([]byte{1, 2, 3})
+	if !verifier.Verify([]byte{1, 2, 3}, signature) {
+		t.Errorf("Expected to verify signature.")
+	}
+}
+
+func TestRSAPEMFiles(t *testing.T) {
+	privDER, _ := FromHex(RSAPrivHex)
+	priv, _ := UnmarshalRSAPrivateKey(privDER)
+	dir := t.TempDir()
+	privFile := filepath.Join(dir, "rsa.key")
+	pubFile := filepath.Join(dir, "rsa.pub")
+
+	if err := RSAPrivateKeyToPEMFile(privFile, priv); err != nil {
+		t.Fatal(err)
+	}
+	if err := RSAPublicKeyToPEMFile(pubFile, &priv.PublicKey); err != nil {
+		t.Fatal(err)
+	}
+	privKey, err := RSAPrivateKeyFromPEMFile(privFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := RSAPublicKeyFromPEMFile(pubFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !priv.Equal(privKey) {
+		t.Error("private keys do not match")
+	}
+	if !priv.PublicKey.Equal(pubKey) {
+		t.Error("public keys do not match")
+	}
+
+	content, _ := os.ReadFile(privFile)
+	der, _ := DecodeX509PEM(content)
+	if !reflect.DeepEqual(der, privDER) {
+		t.Error("Expected PKCS#1 encoded private key")
+	}
+}
+
+func TestUnmarshalRSAKeyFormats(t *testing.T) {
+	privDER, _ := FromHex(RSAPrivHex)
+	priv, _ := UnmarshalRSAPrivateKey(privDER)
+
+	pkcs8, _ := x509.MarshalPKCS8PrivateKey(priv)
+	fromPKCS8, err := UnmarshalRSAPrivateKey(pkcs8)
+	if err != nil || !priv.Equal(fromPKCS8) {
+		t.Errorf("Expected to parse PKCS#8 private key, got %v", err)
+	}
+
+	pkcs1Pub := x509.MarshalPKCS1PublicKey(&priv.PublicKey)
+	fromPKCS1, err := UnmarshalRSAPublicKey(pkcs1Pub)
+	if err != nil || !priv.PublicKey.Equal(fromPKCS1) {
+		t.Errorf("Expected to parse PKCS#1 public key, got %v", err)
+	}
+
+	ecPriv, _, _ := GenerateKeyPairP256()
+	ecPKCS8, _ := x509.MarshalPKCS8PrivateKey(ecPriv)
+	_, err = UnmarshalRSAPrivateKey(ecPKCS8)
+	if !errors.Is(err, ErrUnexpectedKeyType) {
+		t.Errorf("Expected ErrUnexpectedKeyType, got %v", err)
+	}
+}
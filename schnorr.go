@@ -0,0 +1,275 @@
+package crypto
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+)
+
+// BIP-340 Schnorr signatures over secp256k1, as used by Bitcoin taproot.
+// Public keys are the 32 byte X coordinate of a point with an even Y
+// coordinate, signatures are the 32 byte X coordinate of R followed by the
+// 32 byte S. Messages are signed as given, BIP-340 hashes them together with
+// the nonce and key using tagged SHA-256 hashes.
+const (
+	// Secp256k1SchnorrPrivateKeyLength Private key length for BIP-340 Schnorr
+	Secp256k1SchnorrPrivateKeyLength = 32
+	// Secp256k1SchnorrPublicKeyLength Public key length for BIP-340 Schnorr, the
+	// X coordinate only
+	Secp256k1SchnorrPublicKeyLength = 32
+	// Secp256k1SchnorrSignatureLength Signature length for BIP-340 Schnorr,
+	// R.x || S
+	Secp256k1SchnorrSignatureLength = 64
+)
+
+func init() {
+	mustRegisterSuite(
+		"schnorr_secp256k1_bip340",
+		NewSecp256k1SchnorrSigner,
+		NewSecp256k1SchnorrVerifier,
+	)
+}
+
+// Tagged hashes, SHA-256(SHA-256(tag) || SHA-256(tag) || data...), keep the
+// hashes used in each step of the scheme from colliding with each other or
+// with other protocols.
+func bip340TaggedHash(tag string, data ...[]byte) []byte {
+	tagHash := sha256.Sum256([]byte(tag))
+	h := sha256.New()
+	h.Write(tagHash[:])
+	h.Write(tagHash[:])
+	for _, d := range data {
+		h.Write(d)
+	}
+	return h.Sum(nil)
+}
+
+// GenerateSecp256k1SchnorrKeyPair creates a BIP-340 key pair, returning the
+// 32 byte x-only public key and the 32 byte private key.
+func GenerateSecp256k1SchnorrKeyPair() (pub []byte, priv []byte, err error) {
+	privKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	return privKey.PubKey().SerializeCompressed()[1:], privKey.Serialize(), nil
+}
+
+// Secp256k1SchnorrPublicKeyFromPrivate return the 32 byte x-only public key
+// associated with a secp256k1 private key.
+func Secp256k1SchnorrPublicKeyFromPrivate(privKey []byte) ([]byte, error) {
+	key, err := parseSecp256k1PrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	return key.PubKey().SerializeCompressed()[1:], nil
+}
+
+// Lifts an x-only public key to the point with that X coordinate and an even
+// Y coordinate.
+func parseSchnorrPublicKey(pubKey []byte) (*secp256k1.PublicKey, error) {
+	if len(pubKey) != Secp256k1SchnorrPublicKeyLength {
+		return nil, fmt.Errorf("%w: key should be 32 bytes got %d", ErrInvalidPublicKey, len(pubKey))
+	}
+	key, err := secp256k1.ParsePubKey(append([]byte{secp256k1.PubKeyFormatCompressedEven}, pubKey...))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+	}
+	return key, nil
+}
+
+// NewSecp256k1SchnorrVerifier constructor for a BIP-340 Schnorr Verifier
+func NewSecp256k1SchnorrVerifier(pubKey []byte) (Verifier, error) {
+	key, err := parseSchnorrPublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return &schnorrVerifier{
+		publicKey: key,
+		suiteType: "schnorr_secp256k1_bip340",
+	}, nil
+}
+
+// NewSecp256k1SchnorrSigner constructor for a BIP-340 Schnorr Signer. Each
+// signature mixes 32 bytes of auxiliary randomness into the nonce as BIP-340
+// recommends.
+func NewSecp256k1SchnorrSigner(privKey []byte) (Signer, error) {
+	key, err := parseSecp256k1PrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	pubKey, err := parseSchnorrPublicKey(key.PubKey().SerializeCompressed()[1:])
+	if err != nil {
+		return nil, err
+	}
+	return &schnorrSigner{
+		privKey: key,
+		verifier: &schnorrVerifier{
+			publicKey: pubKey,
+			suiteType: "schnorr_secp256k1_bip340",
+		},
+	}, nil
+}
+
+// schnorrVerifier Verifies BIP-340 Schnorr signatures
+type schnorrVerifier struct {
+	publicKey *secp256k1.PublicKey
+	suiteType string
+}
+
+func (s *schnorrVerifier) SuiteType() string {
+	return s.suiteType
+}
+
+// PublicKey returns the public key, the 32 byte X coordinate, see PublicKeyHolder.
+func (s *schnorrVerifier) PublicKey() []byte {
+	return s.publicKey.SerializeCompressed()[1:]
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *schnorrVerifier) KeyID() string {
+	return KeyIDFromPublicKey(s.PublicKey())
+}
+
+func (s *schnorrVerifier) Verify(toVerify []byte, signature []byte) bool {
+	return s.VerifyErr(toVerify, signature) == nil
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *schnorrVerifier) VerifyErr(toVerify []byte, signature []byte) error {
+	return verifySchnorr(s.publicKey, toVerify, signature)
+}
+
+// Verification as specified in BIP-340, the public key must already have an
+// even Y coordinate.
+func verifySchnorr(pubKey *secp256k1.PublicKey, message []byte, signature []byte) error {
+	if len(signature) != Secp256k1SchnorrSignatureLength {
+		return fmt.Errorf(
+			"%w: expected %d bytes, got %d bytes",
+			ErrSignatureLength, Secp256k1SchnorrSignatureLength, len(signature))
+	}
+	var r secp256k1.FieldVal
+	if overflow := r.SetByteSlice(signature[:32]); overflow {
+		return fmt.Errorf("%w: R is not less than the field size", ErrSignatureEncoding)
+	}
+	var sScalar secp256k1.ModNScalar
+	if overflow := sScalar.SetByteSlice(signature[32:]); overflow {
+		return fmt.Errorf("%w: S is not less than the group order", ErrSignatureEncoding)
+	}
+
+	pubBytes := pubKey.SerializeCompressed()[1:]
+	var e secp256k1.ModNScalar
+	e.SetByteSlice(bip340TaggedHash("BIP0340/challenge", signature[:32], pubBytes, message))
+
+	// R = s*G - e*P
+	var P, sG, eP, R secp256k1.JacobianPoint
+	pubKey.AsJacobian(&P)
+	secp256k1.ScalarBaseMultNonConst(&sScalar, &sG)
+	secp256k1.ScalarMultNonConst(e.Negate(), &P, &eP)
+	secp256k1.AddNonConst(&sG, &eP, &R)
+
+	if (R.X.IsZero() && R.Y.IsZero()) || R.Z.IsZero() {
+		return fmt.Errorf("%w: R is the point at infinity", ErrSignatureMismatch)
+	}
+	R.ToAffine()
+	if R.Y.IsOdd() {
+		return fmt.Errorf("%w: R has an odd Y coordinate", ErrSignatureMismatch)
+	}
+	if !R.X.Equals(&r) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+type schnorrSigner struct {
+	privKey  *secp256k1.PrivateKey
+	verifier *schnorrVerifier
+}
+
+func (s *schnorrSigner) Sign(toSign []byte) ([]byte, error) {
+	auxRand := make([]byte, 32)
+	if _, err := rand.Read(auxRand); err != nil {
+		return nil, err
+	}
+	return signSchnorr(s.privKey, toSign, auxRand)
+}
+
+func (s *schnorrSigner) Verify(toVerify []byte, signature []byte) bool {
+	return s.verifier.Verify(toVerify, signature)
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *schnorrSigner) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifier.VerifyErr(toVerify, signature)
+}
+
+func (s *schnorrSigner) SuiteType() string {
+	return s.verifier.SuiteType()
+}
+
+// PublicKey returns the public key, see PublicKeyHolder.
+func (s *schnorrSigner) PublicKey() []byte {
+	return s.verifier.PublicKey()
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *schnorrSigner) KeyID() string {
+	return s.verifier.KeyID()
+}
+
+// Signing as specified in BIP-340 with 32 bytes of auxiliary randomness. The
+// signature is verified before it is returned to guard against faults.
+func signSchnorr(privKey *secp256k1.PrivateKey, message []byte, auxRand []byte) ([]byte, error) {
+	if len(auxRand) != 32 {
+		return nil, errors.New("auxiliary randomness should be 32 bytes got " + fmt.Sprint(len(auxRand)))
+	}
+
+	// d is negated when needed so that P = d*G has an even Y coordinate.
+	d := privKey.Key
+	var P secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&d, &P)
+	P.ToAffine()
+	if P.Y.IsOdd() {
+		d.Negate()
+		P.Y.Negate(1).Normalize()
+	}
+	pubBytes := P.X.Bytes()
+
+	dBytes := d.Bytes()
+	t := bip340TaggedHash("BIP0340/aux", auxRand)
+	for i := range t {
+		t[i] ^= dBytes[i]
+	}
+
+	var k secp256k1.ModNScalar
+	k.SetByteSlice(bip340TaggedHash("BIP0340/nonce", t, pubBytes[:], message))
+	if k.IsZero() {
+		return nil, errors.New("BIP-340 nonce is zero")
+	}
+	var R secp256k1.JacobianPoint
+	secp256k1.ScalarBaseMultNonConst(&k, &R)
+	R.ToAffine()
+	if R.Y.IsOdd() {
+		k.Negate()
+	}
+	rBytes := R.X.Bytes()
+
+	var e secp256k1.ModNScalar
+	e.SetByteSlice(bip340TaggedHash("BIP0340/challenge", rBytes[:], pubBytes[:], message))
+
+	// s = k + e*d
+	sScalar := new(secp256k1.ModNScalar).Mul2(&e, &d).Add(&k)
+	sBytes := sScalar.Bytes()
+
+	signature := make([]byte, Secp256k1SchnorrSignatureLength)
+	copy(signature, rBytes[:])
+	copy(signature[32:], sBytes[:])
+
+	pubKey := secp256k1.NewPublicKey(&P.X, &P.Y)
+	if err := verifySchnorr(pubKey, message, signature); err != nil {
+		return nil, fmt.Errorf("created signature does not verify: %w", err)
+	}
+	return signature, nil
+}
@@ -0,0 +1,358 @@
+package crypto
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	secp256k1ecdsa "github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// secp256k1 is the curve used by Bitcoin and Ethereum. The suites in this file
+// produce signatures that are byte compatible with go-ethereum's crypto.Sign,
+// a 32 byte R, a 32 byte S and a 1 byte recovery id V (0 or 1). The nonce is
+// chosen as described in RFC 6979 so signing is deterministic, and S is
+// always normalized to the lower half of the curve order.
+//
+// The ecdsa_secp256k1_sha-256 suite is the ES256K algorithm of RFC 8812, it
+// hashes with SHA-256 and its signatures are R || S without a recovery id.
+const (
+	// Secp256k1PrivateKeyLength Private key length for secp256k1
+	Secp256k1PrivateKeyLength = 32
+	// Secp256k1PublicKeyLength Public key length for secp256k1, the X and Y
+	// coordinates without the 0x04 prefix
+	Secp256k1PublicKeyLength = 64
+	// Secp256k1SignatureLength Signature length for recoverable secp256k1
+	// signatures, R || S || V
+	Secp256k1SignatureLength = 65
+	// Secp256k1Sha_256SignatureLength Signature length for
+	// ecdsa_secp256k1_sha-256 signatures, R || S
+	Secp256k1Sha_256SignatureLength = 64
+)
+
+func init() {
+	mustRegisterECDSASuite(
+		"ecdsa_secp256k1_keccak256",
+		NewSecp256k1Keccak256Signer,
+		NewSecp256k1Keccak256Verifier,
+	)
+	mustRegisterRecoverer("ecdsa_secp256k1_keccak256", RecoverSecp256k1Keccak256PublicKey)
+	mustRegisterECDSASuite(
+		"ecdsa_secp256k1_sha-256",
+		NewSecp256k1Sha_256Signer,
+		NewSecp256k1Sha_256Verifier,
+	)
+}
+
+// GenerateSecp256k1KeyPair creates a secp256k1 key pair, returning the 64 byte
+// public key and the 32 byte private key.
+func GenerateSecp256k1KeyPair() (pub []byte, priv []byte, err error) {
+	privKey, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, nil, err
+	}
+	return privKey.PubKey().SerializeUncompressed()[1:], privKey.Serialize(), nil
+}
+
+// Secp256k1PublicKeyFromPrivate return the 64 byte public key associated with a
+// secp256k1 private key.
+func Secp256k1PublicKeyFromPrivate(privKey []byte) ([]byte, error) {
+	key, err := parseSecp256k1PrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	return key.PubKey().SerializeUncompressed()[1:], nil
+}
+
+func parseSecp256k1PrivateKey(privKey []byte) (*secp256k1.PrivateKey, error) {
+	if len(privKey) != Secp256k1PrivateKeyLength {
+		return nil, errors.New("key should be 32 bytes got " + fmt.Sprint(len(privKey)))
+	}
+	var k secp256k1.ModNScalar
+	if overflow := k.SetByteSlice(privKey); overflow || k.IsZero() {
+		return nil, errors.New("invalid secp256k1 private key")
+	}
+	return secp256k1.NewPrivateKey(&k), nil
+}
+
+func parseSecp256k1PublicKey(pubKey []byte) (*secp256k1.PublicKey, error) {
+	if len(pubKey) != Secp256k1PublicKeyLength {
+		return nil, fmt.Errorf("%w: key should be 64 bytes got %d", ErrInvalidPublicKey, len(pubKey))
+	}
+	key, err := secp256k1.ParsePubKey(append([]byte{0x04}, pubKey...))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPublicKey, err)
+	}
+	return key, nil
+}
+
+// NewSecp256k1Keccak256Verifier constructor for a secp256k1 Verifier that
+// hashes messages with Keccak256, as Ethereum does.
+func NewSecp256k1Keccak256Verifier(pubKey []byte) (Verifier, error) {
+	key, err := parseSecp256k1PublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return &secp256k1Verifier{
+		publicKey:   key,
+		hasher:      &Keccak256Hasher{},
+		recoverable: true,
+		suiteType:   "ecdsa_secp256k1_keccak256",
+	}, nil
+}
+
+// NewSecp256k1Keccak256Signer constructor for a secp256k1 Signer that hashes
+// messages with Keccak256, as Ethereum does.
+func NewSecp256k1Keccak256Signer(privKey []byte) (Signer, error) {
+	key, err := parseSecp256k1PrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	hasher := &Keccak256Hasher{}
+	return &secp256k1Signer{
+		privKey: key,
+		hasher:  hasher,
+		verifier: &secp256k1Verifier{
+			publicKey:   key.PubKey(),
+			hasher:      hasher,
+			recoverable: true,
+			suiteType:   "ecdsa_secp256k1_keccak256",
+		},
+	}, nil
+}
+
+// NewSecp256k1Sha_256Verifier constructor for a secp256k1 Verifier that
+// hashes messages with SHA-256 and verifies R || S signatures.
+func NewSecp256k1Sha_256Verifier(pubKey []byte) (Verifier, error) {
+	key, err := parseSecp256k1PublicKey(pubKey)
+	if err != nil {
+		return nil, err
+	}
+	return &secp256k1Verifier{
+		publicKey: key,
+		hasher:    &Sha_256Hasher{},
+		suiteType: "ecdsa_secp256k1_sha-256",
+	}, nil
+}
+
+// NewSecp256k1Sha_256Signer constructor for a secp256k1 Signer that hashes
+// messages with SHA-256 and creates R || S signatures.
+func NewSecp256k1Sha_256Signer(privKey []byte) (Signer, error) {
+	key, err := parseSecp256k1PrivateKey(privKey)
+	if err != nil {
+		return nil, err
+	}
+	hasher := &Sha_256Hasher{}
+	return &secp256k1Signer{
+		privKey: key,
+		hasher:  hasher,
+		verifier: &secp256k1Verifier{
+			publicKey: key.PubKey(),
+			hasher:    hasher,
+			suiteType: "ecdsa_secp256k1_sha-256",
+		},
+	}, nil
+}
+
+// secp256k1Verifier Verifies recoverable R || S || V signatures, or R || S
+// signatures when recoverable is false. Recoverable signatures with a high S
+// are rejected, as they are by Ethereum, R || S signatures only when lowS is
+// set, see NewLowSVerifier.
+type secp256k1Verifier struct {
+	publicKey   *secp256k1.PublicKey
+	hasher      Hasher
+	recoverable bool
+	lowS        bool
+	suiteType   string
+}
+
+func (s *secp256k1Verifier) SuiteType() string {
+	return s.suiteType
+}
+
+// PublicKey returns the public key, the 64 byte X || Y, see PublicKeyHolder.
+func (s *secp256k1Verifier) PublicKey() []byte {
+	return s.publicKey.SerializeUncompressed()[1:]
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *secp256k1Verifier) KeyID() string {
+	return KeyIDFromPublicKey(s.PublicKey())
+}
+
+func (s *secp256k1Verifier) Verify(toVerify []byte, signature []byte) bool {
+	return s.VerifyErr(toVerify, signature) == nil
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *secp256k1Verifier) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifyDigest(s.hasher.Hash(toVerify), signature)
+}
+
+func (s *secp256k1Verifier) verifyDigest(digest []byte, signature []byte) error {
+	parse := parseSecp256k1Signature
+	if !s.recoverable {
+		parse = parseSecp256k1Sha_256Signature
+	}
+	sig, err := parse(signature)
+	if err != nil {
+		return err
+	}
+	if S := sig.S(); s.lowS && S.IsOverHalfOrder() {
+		return fmt.Errorf("%w: S is not in the lower half of the order", ErrSignatureEncoding)
+	}
+	if !sig.Verify(digest, s.publicKey) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+func (s *secp256k1Verifier) newHash() hash.Hash {
+	hashFunc, _ := newStreamHash(s.hasher)
+	return hashFunc
+}
+
+// VerifyDigest verifies a signature over a digest, see DigestVerifier.
+func (s *secp256k1Verifier) VerifyDigest(digest []byte, signature []byte) bool {
+	return verifyCheckedDigest(s, digest, signature)
+}
+
+// Parses an R || S || V signature, rejecting out of range values.
+func parseSecp256k1Signature(signature []byte) (*secp256k1ecdsa.Signature, error) {
+	if len(signature) != Secp256k1SignatureLength {
+		return nil, fmt.Errorf(
+			"%w: expected %d bytes, got %d bytes",
+			ErrSignatureLength, Secp256k1SignatureLength, len(signature))
+	}
+	var r, s secp256k1.ModNScalar
+	if err := setSecp256k1RS(&r, &s, signature); err != nil {
+		return nil, err
+	}
+	if s.IsOverHalfOrder() {
+		return nil, fmt.Errorf("%w: S is not in the lower half of the order", ErrSignatureEncoding)
+	}
+	if signature[64] > 1 {
+		return nil, fmt.Errorf("%w: V must be 0 or 1", ErrSignatureEncoding)
+	}
+	return secp256k1ecdsa.NewSignature(&r, &s), nil
+}
+
+// Parses an R || S signature, S may be in either half of the order.
+func parseSecp256k1Sha_256Signature(signature []byte) (*secp256k1ecdsa.Signature, error) {
+	if len(signature) != Secp256k1Sha_256SignatureLength {
+		return nil, fmt.Errorf(
+			"%w: expected %d bytes, got %d bytes",
+			ErrSignatureLength, Secp256k1Sha_256SignatureLength, len(signature))
+	}
+	var r, s secp256k1.ModNScalar
+	if err := setSecp256k1RS(&r, &s, signature); err != nil {
+		return nil, err
+	}
+	return secp256k1ecdsa.NewSignature(&r, &s), nil
+}
+
+func setSecp256k1RS(r, s *secp256k1.ModNScalar, signature []byte) error {
+	if overflow := r.SetByteSlice(signature[:32]); overflow || r.IsZero() {
+		return fmt.Errorf("%w: R out of range", ErrSignatureEncoding)
+	}
+	if overflow := s.SetByteSlice(signature[32:64]); overflow || s.IsZero() {
+		return fmt.Errorf("%w: S out of range", ErrSignatureEncoding)
+	}
+	return nil
+}
+
+// RecoverSecp256k1Keccak256PublicKey returns the 64 byte public key that
+// created an ecdsa_secp256k1_keccak256 signature over message, in the same
+// way as Ethereum's ecrecover.
+func RecoverSecp256k1Keccak256PublicKey(message []byte, signature []byte) ([]byte, error) {
+	key, err := recoverSecp256k1((&Keccak256Hasher{}).Hash(message), signature)
+	if err != nil {
+		return nil, err
+	}
+	return key.SerializeUncompressed()[1:], nil
+}
+
+// Recovers the key that signed a 32 byte hash.
+func recoverSecp256k1(digest []byte, signature []byte) (*secp256k1.PublicKey, error) {
+	if _, err := parseSecp256k1Signature(signature); err != nil {
+		return nil, err
+	}
+	compact := make([]byte, Secp256k1SignatureLength)
+	compact[0] = signature[64] + 27
+	copy(compact[1:], signature[:64])
+	key, _, err := secp256k1ecdsa.RecoverCompact(compact, digest)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrSignatureEncoding, err)
+	}
+	return key, nil
+}
+
+type secp256k1Signer struct {
+	privKey  *secp256k1.PrivateKey
+	hasher   Hasher
+	verifier *secp256k1Verifier
+}
+
+func (s *secp256k1Signer) Sign(toSign []byte) ([]byte, error) {
+	return s.signDigest(s.hasher.Hash(toSign))
+}
+
+func (s *secp256k1Signer) signDigest(digest []byte) ([]byte, error) {
+	signature := signSecp256k1(s.privKey, digest)
+	if !s.verifier.recoverable {
+		return signature[:Secp256k1Sha_256SignatureLength], nil
+	}
+	return signature, nil
+}
+
+// Signs a 32 byte hash, converting the compact <V + 27> || R || S signature to
+// R || S || V.
+func signSecp256k1(privKey *secp256k1.PrivateKey, hash []byte) []byte {
+	compact := secp256k1ecdsa.SignCompact(privKey, hash, false)
+	signature := make([]byte, Secp256k1SignatureLength)
+	copy(signature, compact[1:])
+	signature[64] = compact[0] - 27
+	return signature
+}
+
+func (s *secp256k1Signer) Verify(toVerify []byte, signature []byte) bool {
+	return s.verifier.Verify(toVerify, signature)
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *secp256k1Signer) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifier.VerifyErr(toVerify, signature)
+}
+
+func (s *secp256k1Signer) verifyDigest(digest []byte, signature []byte) error {
+	return s.verifier.verifyDigest(digest, signature)
+}
+
+func (s *secp256k1Signer) newHash() hash.Hash {
+	return s.verifier.newHash()
+}
+
+// SignDigest signs a digest computed with the suite's hash, see DigestSigner.
+func (s *secp256k1Signer) SignDigest(digest []byte) ([]byte, error) {
+	return signCheckedDigest(s, digest)
+}
+
+// VerifyDigest verifies a signature over a digest, see DigestVerifier.
+func (s *secp256k1Signer) VerifyDigest(digest []byte, signature []byte) bool {
+	return verifyCheckedDigest(s, digest, signature)
+}
+
+func (s *secp256k1Signer) SuiteType() string {
+	return s.verifier.SuiteType()
+}
+
+// PublicKey returns the public key, see PublicKeyHolder.
+func (s *secp256k1Signer) PublicKey() []byte {
+	return s.verifier.PublicKey()
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *secp256k1Signer) KeyID() string {
+	return s.verifier.KeyID()
+}
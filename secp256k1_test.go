@@ -0,0 +1,224 @@
+package crypto
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// Key and signatures generated with go-ethereum's crypto.Sign over the
+// Keccak256 hash of the message.
+const Secp256k1PrivHex = "289c2857d4598e37fb9647507e47a309d6133539bf21a8b9cb6df88fd5232032"
+const Secp256k1PubHex = "7db227d7094ce215c3a0f57e1bcc732551fe351f94249471934567e0f5dc1bf795962b8cccb87a2eb56b29fbe37d614e2f4c3c45b789ae4f1f51f4cb21972ffd"
+
+var secp256k1TestCases = []struct {
+	message   []byte
+	signature string
+}{
+	{[]byte{}, "b415397b439cc1eaab587a70717499b56b6cbe63037c241b2eaca2e833a6da097002b11c9611964e97212c82eab9613531f40e065d4d32e32ef31d68fedd977501"},
+	{[]byte{1, 2}, "502a7094f18e84c05e229c8706dd072e33687153266470b3a89f1526a5f3c1c17a0d768f1151c3f3b497ab5c7cf39729655b73f396a1e79bf003dcf65db2fe2201"},
+	{[]byte("hello world"), "89b8af6348c2518e5a492647f0e3fa11df1dc04858dcd82616700777824f19d65f55d1ce01aac5f45489bb9339c7f2f5c426590d0a396f1568ff513e7c78e53b00"},
+}
+
+func TestSecp256k1SuiteType(t *testing.T) {
+	signer := secp256k1Signer{
+		verifier: &secp256k1Verifier{suiteType: "Test"},
+	}
+	expected := "Test"
+	result := signer.SuiteType()
+	if expected != result {
+		t.Errorf("Got %s, expected %s", result, expected)
+	}
+}
+
+func TestSecp256k1Sign(t *testing.T) {
+	privKey, _ := FromHex(Secp256k1PrivHex)
+	signer, err := NewSecp256k1Keccak256Signer(privKey)
+	if err != nil {
+		t.Fatalf("Got a constructor error %s", err)
+	}
+	if signer.SuiteType() != "ecdsa_secp256k1_keccak256" {
+		t.Errorf("Expected suite type ecdsa_secp256k1_keccak256 got %s", signer.SuiteType())
+	}
+	for _, tt := range secp256k1TestCases {
+		t.Run(ToHex(tt.message), func(t *testing.T) {
+			signature, err := signer.Sign(tt.message)
+			if err != nil {
+				t.Fatalf("Got a sign error %s", err)
+			}
+			if ToHex(signature) != tt.signature {
+				t.Errorf("Expected %s, signature was %x.", tt.signature, signature)
+			}
+			if !signer.Verify(tt.message, signature) {
+				t.Errorf("Signer didn't verify its own signature.")
+			}
+		})
+	}
+}
+
+func TestSecp256k1Verify(t *testing.T) {
+	pubKey, _ := FromHex(Secp256k1PubHex)
+	verifier, err := NewSecp256k1Keccak256Verifier(pubKey)
+	if err != nil {
+		t.Fatalf("Got a constructor error %s", err)
+	}
+	for _, tt := range secp256k1TestCases {
+		t.Run(ToHex(tt.message), func(t *testing.T) {
+			signature, _ := FromHex(tt.signature)
+			if !verifier.Verify(tt.message, signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+			if verifier.Verify(append(tt.message, 1), signature) {
+				t.Errorf("Expected signature over another message to not verify.")
+			}
+		})
+	}
+}
+
+func TestSecp256k1VerifyRejectsMalformed(t *testing.T) {
+	pubKey, _ := FromHex(Secp256k1PubHex)
+	verifier, _ := NewSecp256k1Keccak256Verifier(pubKey)
+	message := secp256k1TestCases[1].message
+	signature, _ := FromHex(secp256k1TestCases[1].signature)
+
+	if verifier.Verify(message, signature[:64]) {
+		t.Errorf("Expected 64 byte signature to not verify.")
+	}
+
+	badV := append([]byte{}, signature...)
+	badV[64] = 27
+	if verifier.Verify(message, badV) {
+		t.Errorf("Expected V outside of 0 and 1 to not verify.")
+	}
+
+	// n - s is a valid signature for the same message, but with a high S.
+	_, err := parseSecp256k1Signature(highSSecp256k1Signature(signature))
+	if !errors.Is(err, ErrSignatureEncoding) {
+		t.Errorf("Expected ErrSignatureEncoding for high S, got %v", err)
+	}
+	if verifier.Verify(message, highSSecp256k1Signature(signature)) {
+		t.Errorf("Expected high S signature to not verify.")
+	}
+}
+
+// Replaces S with N - S, flipping V to keep the signature recoverable.
+func highSSecp256k1Signature(signature []byte) []byte {
+	n, _ := FromHex("fffffffffffffffffffffffffffffffebaaedce6af48a03bbfd25e8cd0364141")
+	high := append([]byte{}, signature...)
+	borrow := 0
+	for i := 31; i >= 0; i-- {
+		v := int(n[i]) - int(signature[32+i]) - borrow
+		borrow = 0
+		if v < 0 {
+			v += 256
+			borrow = 1
+		}
+		high[32+i] = byte(v)
+	}
+	high[64] ^= 1
+	return high
+}
+
+func TestSecp256k1KeySize(t *testing.T) {
+	key := []byte{1, 2, 3}
+	_, sigErr := NewSecp256k1Keccak256Signer(key)
+	_, verErr := NewSecp256k1Keccak256Verifier(key)
+
+	if sigErr == nil {
+		t.Errorf("Did not get signature error.")
+	}
+
+	if verErr == nil {
+		t.Errorf("Did not get verifier error.")
+	}
+}
+
+func TestSecp256k1InvalidKeys(t *testing.T) {
+	_, err := NewSecp256k1Keccak256Signer(make([]byte, 32))
+	if err == nil {
+		t.Errorf("Expected error for zero private key.")
+	}
+	_, err = NewSecp256k1Keccak256Verifier(make([]byte, 64))
+	if err == nil {
+		t.Errorf("Expected error for public key not on the curve.")
+	}
+}
+
+func TestSecp256k1PublicFromPrivate(t *testing.T) {
+	privKey, _ := FromHex(Secp256k1PrivHex)
+
+	pubKey, _ := Secp256k1PublicKeyFromPrivate(privKey)
+
+	expected, _ := FromHex(Secp256k1PubHex)
+	if !reflect.DeepEqual(pubKey, expected) {
+		t.Errorf("Expected %x, pubkey was %x.", expected, pubKey)
+	}
+}
+
+func TestGenerateSecp256k1KeyPair(t *testing.T) {
+	pub, priv, err := GenerateSecp256k1KeyPair()
+	if err != nil {
+		t.Fatalf("Got a key generation error %s", err)
+	}
+	signer, _ := NewSecp256k1Keccak256Signer(priv)
+	verifier, err := NewSecp256k1Keccak256Verifier(pub)
+	if err != nil {
+		t.Fatalf("Got a constructor error %s", err)
+	}
+	signature, _ := signer.Sign([]byte{1, 2, 3})
+	if !verifier.Verify([]byte{1, 2, 3}, signature) {
+		t.Errorf("Expected to verify signature.")
+	}
+}
+
+// Generated with go-ethereum's crypto.Sign over the SHA-256 hash of the
+// message, without the recovery id.
+var secp256k1Sha_256TestCases = []struct {
+	message   []byte
+	signature string
+}{
+	{[]byte{}, "a827ad30326a41bedfa260bcde2db2d51259b39821c3b2898db053cf3daed5e86f9200ba27fabd468197d896117f650b531d8651189ecc8bb704aca1859ffafb"},
+	{[]byte{1, 2}, "b5bea2b02c643e1c02d91c986e3d2cf0b196c3f2649a9df3e5b932f119e22d5149c6babb53e05c73051a9fe7cec95d0dd8f372a64fdc97d0c54022bacb3aed5f"},
+	{[]byte("hello world"), "33f2d5f0c7c776049e7955b5f33c1a5e85c7fe9822909b10a7dee7913f5d70ba33c63195f3d0976d52564ae071d78661e1b36a6618ffb0ee1f74efac0da33e2d"},
+}
+
+func TestSecp256k1Sha_256(t *testing.T) {
+	privKey, _ := FromHex(Secp256k1PrivHex)
+	pubKey, _ := FromHex(Secp256k1PubHex)
+	signer, err := NewSecp256k1Sha_256Signer(privKey)
+	if err != nil {
+		t.Fatalf("Got a constructor error %s", err)
+	}
+	verifier, err := NewSecp256k1Sha_256Verifier(pubKey)
+	if err != nil {
+		t.Fatalf("Got a constructor error %s", err)
+	}
+	if signer.SuiteType() != "ecdsa_secp256k1_sha-256" {
+		t.Errorf("Expected suite type ecdsa_secp256k1_sha-256 got %s", signer.SuiteType())
+	}
+	for _, tt := range secp256k1Sha_256TestCases {
+		t.Run(ToHex(tt.message), func(t *testing.T) {
+			signature, err := signer.Sign(tt.message)
+			if err != nil {
+				t.Fatalf("Got a sign error %s", err)
+			}
+			if ToHex(signature) != tt.signature {
+				t.Errorf("Expected %s, signature was %x.", tt.signature, signature)
+			}
+			if !verifier.Verify(tt.message, signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+			if verifier.Verify(append(tt.message, 1), signature) {
+				t.Errorf("Expected signature over another message to not verify.")
+			}
+			// Without a recovery id either S is a valid signature.
+			highS := highSSecp256k1Signature(append(signature, 0))[:64]
+			if !verifier.Verify(tt.message, highS) {
+				t.Errorf("Expected high S signature to verify.")
+			}
+			if verifier.Verify(tt.message, append(signature, 0)) {
+				t.Errorf("Expected 65 byte signature to not verify.")
+			}
+		})
+	}
+}
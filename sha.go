@@ -3,6 +3,7 @@ package crypto
 import (
 	"crypto/sha256"
 	"crypto/sha512"
+	"hash"
 
 	"golang.org/x/crypto/sha3"
 )
@@ -24,6 +25,10 @@ func (h *Sha3_256Hasher) HashHex(input ...[]byte) string {
 	return hashHex(sha3.New256(), input...)
 }
 
+func (h *Sha3_256Hasher) newHash() hash.Hash {
+	return sha3.New256()
+}
+
 // Sha3_512 is a SHA-3-512 hasher. Its generic security strength is
 // 512 bits against preimage attacks, and 256 bits against collision attacks.
 // data is an arbitrary length bytes slice returns 64 bytes ( 512 bits ) hash
@@ -41,6 +46,10 @@ func (h *Sha3_512Hasher) HashHex(input ...[]byte) string {
 	return hashHex(sha3.New512(), input...)
 }
 
+func (h *Sha3_512Hasher) newHash() hash.Hash {
+	return sha3.New512()
+}
+
 // Sha_256 is a SHA-256 hasher. Its generic security strength is
 // 256 bits against preimage attacks, and 128 bits against collision attacks.
 // data is an arbitrary length bytes slice returns 32 bytes ( 256 bits ) hash
@@ -58,6 +67,31 @@ func (h *Sha_256Hasher) HashHex(input ...[]byte) string {
 	return hashHex(sha256.New(), input...)
 }
 
+func (h *Sha_256Hasher) newHash() hash.Hash {
+	return sha256.New()
+}
+
+// Sha_384 is a SHA-384 hasher. Its generic security strength is
+// 384 bits against preimage attacks, and 192 bits against collision attacks.
+// data is an arbitrary length bytes slice returns 48 bytes ( 384 bits ) hash
+// of data.
+//
+// Implements the crypto.Hasher interface.
+type Sha_384Hasher struct {
+}
+
+func (h *Sha_384Hasher) Hash(input ...[]byte) []byte {
+	return hashBytes(sha512.New384(), input...)
+}
+
+func (h *Sha_384Hasher) HashHex(input ...[]byte) string {
+	return hashHex(sha512.New384(), input...)
+}
+
+func (h *Sha_384Hasher) newHash() hash.Hash {
+	return sha512.New384()
+}
+
 // Sha_512 is a SHA-512 hasher. Its generic security strength is
 // 512 bits against preimage attacks, and 256 bits against collision attacks.
 // data is an arbitrary length bytes slice returns 64 bytes ( 512 bits ) hash
@@ -74,3 +108,7 @@ func (h *Sha_512Hasher) Hash(input ...[]byte) []byte {
 func (h *Sha_512Hasher) HashHex(input ...[]byte) string {
 	return hashHex(sha512.New(), input...)
 }
+
+func (h *Sha_512Hasher) newHash() hash.Hash {
+	return sha512.New()
+}
@@ -22,6 +22,10 @@ var shaTestCases = []struct {
 	{&Sha_256Hasher{}, [][]byte{{}}, "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"},
 	{&Sha_256Hasher{}, [][]byte{[]byte("asdf")}, "f0e4c2f76c58916ec258f246851bea091d14d4247a2fc3e18694461b1816e13b"},
 	{&Sha_256Hasher{}, [][]byte{[]byte("asdf"), []byte("qwer")}, "2cbe13972c4067ebee6437e9bf8b0efa1d869357b4289c3b1b830bd2f602afcd"},
+	{&Sha_384Hasher{}, nil, "38b060a751ac96384cd9327eb1b1e36a21fdb71114be07434c0cc7bf63f6e1da274edebfe76f65fbd51ad2f14898b95b"},
+	{&Sha_384Hasher{}, [][]byte{{}}, "38b060a751ac96384cd9327eb1b1e36a21fdb71114be07434c0cc7bf63f6e1da274edebfe76f65fbd51ad2f14898b95b"},
+	{&Sha_384Hasher{}, [][]byte{[]byte("asdf")}, "a69e7df30b24c042ec540ccbbdbfb1562c85787038c885749c1e408e2d62fa36642cd0075fa351e822e2b8a59139cd9d"},
+	{&Sha_384Hasher{}, [][]byte{[]byte("asdf"), []byte("qwer")}, "4f53fe30dc7b1964159bb51c97fa3ca013171ba5ca1ccf3e34db3d22495f75eb8242691ffb2c4fd33040228879cfb563"},
 	{&Sha_512Hasher{}, nil, "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e"},
 	{&Sha_512Hasher{}, [][]byte{{}}, "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e"},
 	{&Sha_512Hasher{}, [][]byte{[]byte("asdf")}, "401b09eab3c013d4ca54922bb802bec8fd5318192b0a75f201d8b3727429080fb337591abd3e44453b954555b7a0812e1081c39b740293f765eae731f5a65ed1"},
@@ -75,6 +79,14 @@ func BenchmarkSha_256(b *testing.B) {
 	}
 }
 
+func BenchmarkSha_384(b *testing.B) {
+	hasher := Sha_384Hasher{}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		hasher.Hash([]byte("qwerty"))
+	}
+}
+
 func BenchmarkSha_512(b *testing.B) {
 	hasher := Sha_512Hasher{}
 	b.ResetTimer()
@@ -1,6 +1,8 @@
 package crypto
 
 import (
+	"hash"
+
 	"golang.org/x/crypto/sha3"
 )
 
@@ -25,3 +27,33 @@ func (h *Shake256Hasher) Hash(input ...[]byte) []byte {
 func (h *Shake256Hasher) HashHex(input ...[]byte) string {
 	return ToHex(h.Hash(input...))
 }
+
+func (h *Shake256Hasher) newHash() hash.Hash {
+	return newShake256Hash()
+}
+
+// shake256Hash adapts SHAKE256 with a 256bit output to the standard library
+// hash.Hash interface, so it can be used where a fixed size hash function is
+// expected, e.g. as the HMAC for RFC 6979 nonces.
+type shake256Hash struct {
+	sha3.ShakeHash
+}
+
+func newShake256Hash() hash.Hash {
+	return &shake256Hash{ShakeHash: sha3.NewShake256()}
+}
+
+func (h *shake256Hash) Sum(b []byte) []byte {
+	digest := make([]byte, h.Size())
+	h.Clone().Read(digest)
+	return append(b, digest...)
+}
+
+func (h *shake256Hash) Size() int {
+	return 32
+}
+
+// BlockSize is the rate of SHAKE256 in bytes.
+func (h *shake256Hash) BlockSize() int {
+	return 136
+}
@@ -39,6 +39,23 @@ func TestShakeHasherHash(t *testing.T) {
 	}
 }
 
+func TestShake256HashSum(t *testing.T) {
+	for _, tt := range shakeTestCases {
+		t.Run(tt.expected, func(t *testing.T) {
+			h := newShake256Hash()
+			for _, b := range tt.input {
+				h.Write(b)
+			}
+			// Sum must not change the state of the hash.
+			first := ToHex(h.Sum(nil))
+			second := ToHex(h.Sum(nil))
+			if first != tt.expected || second != tt.expected {
+				t.Errorf("Got %s and %s, want %s", first, second, tt.expected)
+			}
+		})
+	}
+}
+
 func BenchmarkShake256(b *testing.B) {
 	hasher := Shake256Hasher{}
 	b.ResetTimer()
@@ -1,5 +1,10 @@
 package crypto
 
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
 // Suite signer/verifier should return some information about what type of signature
 // it is generating. An example may be ecdsa_p256_keccak256 indicating an
 // eliptic curve signing algorithm using curve p256 and hashing with
@@ -16,12 +21,61 @@ type Verifier interface {
 	Verify(toVerify []byte, signature []byte) bool
 }
 
+// ErrorVerifier is implemented by verifiers that can explain why a signature
+// does not verify. All of the verifiers and signers in this package implement
+// it, e.g.
+//
+//	err := verifier.(ErrorVerifier).VerifyErr(message, signature)
+//	if errors.Is(err, ErrSignatureLength) { ... }
+type ErrorVerifier interface {
+	Verifier
+	// VerifyErr returns nil if signature is valid, otherwise an error wrapping
+	// ErrSignatureLength, ErrSignatureEncoding, ErrInvalidPublicKey or
+	// ErrSignatureMismatch. Verify returns true exactly when VerifyErr returns
+	// nil.
+	VerifyErr(toVerify []byte, signature []byte) error
+}
+
+// VerifyErr verifies signature with verifier, returning why it failed when
+// verifier is an ErrorVerifier and ErrSignatureMismatch otherwise.
+func VerifyErr(verifier Verifier, toVerify []byte, signature []byte) error {
+	if v, ok := verifier.(ErrorVerifier); ok {
+		return v.VerifyErr(toVerify, signature)
+	}
+	if !verifier.Verify(toVerify, signature) {
+		return fmt.Errorf("%w: %s", ErrSignatureMismatch, verifier.SuiteType())
+	}
+	return nil
+}
+
+// PublicKeyHolder is implemented by signers and verifiers that hold a single
+// public key. Every signer does, as do the verifiers built from public key
+// data. Verifiers such as the multisig verifier and the Ethereum address
+// verifier do not.
+type PublicKeyHolder interface {
+	// PublicKey returns the public key in the format taken by the suite's
+	// verifier constructor, so it can be passed to NewVerifierForSuite.
+	PublicKey() []byte
+	// KeyID returns a fingerprint of the public key, see KeyIDFromPublicKey.
+	KeyID() string
+}
+
+// KeyIDFromPublicKey returns the key ID of a public key in the format taken by
+// its suite's verifier constructor, the hex encoded SHA-256 hash of the key.
+// The ID does not depend on the suite, a signer and a verifier of the same key
+// have the same ID.
+func KeyIDFromPublicKey(pubKey []byte) string {
+	digest := sha256.Sum256(pubKey)
+	return ToHex(digest[:])
+}
+
 // Signer abstraction around signing. This can be a useful abstraction if you don't
 // particularly care about the key/signature format but simply want to create a
 // cryptographic signer of a certin type. We assume that if you can sign that
 // you can also verify (especially in the case of ecdsa.)
 type Signer interface {
 	Verifier
+	PublicKeyHolder
 	Sign(toSign []byte) ([]byte, error)
 }
 
@@ -36,6 +90,8 @@ type MockSigner struct {
 	ToSign     []byte
 	SignSigRet []byte
 	SignErrRet error
+
+	PublicKeyRet []byte
 }
 
 // Sign mock.
@@ -51,6 +107,24 @@ func (s *MockSigner) Verify(toVerify []byte, signature []byte) bool {
 	return s.VerifyRet
 }
 
+// VerifyErr mock, returns ErrSignatureMismatch when VerifyRet is false.
+func (s *MockSigner) VerifyErr(toVerify []byte, signature []byte) error {
+	if !s.Verify(toVerify, signature) {
+		return ErrSignatureMismatch
+	}
+	return nil
+}
+
+// PublicKey mock.
+func (s *MockSigner) PublicKey() []byte {
+	return s.PublicKeyRet
+}
+
+// KeyID mock, the key ID of PublicKeyRet.
+func (s *MockSigner) KeyID() string {
+	return KeyIDFromPublicKey(s.PublicKeyRet)
+}
+
 // SuiteType mock.
 func (s *MockSigner) SuiteType() string {
 	return s.Suite
@@ -0,0 +1,200 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestVerifyErr(t *testing.T) {
+	message := []byte{1, 2, 3}
+	for _, tt := range registryTestCases {
+		t.Run(tt.suiteType, func(t *testing.T) {
+			privKey, _ := FromHex(tt.privKeyHex)
+			pubKey, _ := FromHex(tt.pubKeyHex)
+			signer, err := NewSignerForSuite(tt.suiteType, privKey)
+			if err != nil {
+				t.Fatalf("Error creating the signer: %s", err)
+			}
+			verifier, err := NewVerifierForSuite(tt.suiteType, pubKey)
+			if err != nil {
+				t.Fatalf("Error creating the verifier: %s", err)
+			}
+			signature, err := signer.Sign(message)
+			if err != nil {
+				t.Fatalf("Error signing: %s", err)
+			}
+
+			testCases := []struct {
+				name      string
+				message   []byte
+				signature []byte
+				expected  error
+			}{
+				{"valid", message, signature, nil},
+				{"other message", []byte{1, 2, 4}, signature, ErrSignatureMismatch},
+				{"short", message, signature[:len(signature)-1], ErrSignatureLength},
+				{"long", message, append(append([]byte{}, signature...), 0), ErrSignatureLength},
+			}
+			for _, tc := range testCases {
+				for _, v := range []Verifier{verifier, signer} {
+					err := v.(ErrorVerifier).VerifyErr(tc.message, tc.signature)
+					if !errors.Is(err, tc.expected) || (err == nil) != (tc.expected == nil) {
+						t.Errorf("%s: got %v, expected %v", tc.name, err, tc.expected)
+					}
+					if v.Verify(tc.message, tc.signature) != (err == nil) {
+						t.Errorf("%s: Verify does not agree with VerifyErr.", tc.name)
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestVerifyErrEncoding(t *testing.T) {
+	message := []byte{1, 2, 3}
+	testCases := []struct {
+		suiteType string
+		pubKeyHex string
+		signature []byte
+	}{
+		// R and S are not less than the group order.
+		{"ecdsa_P256_sha-256", P256PubHex, bytes.Repeat([]byte{0xff}, 64)},
+		{"ecdsa_P256_sha-256", P256PubHex, make([]byte, 64)},
+		{"ecdsa_secp256k1_sha-256", Secp256k1PubHex, bytes.Repeat([]byte{0xff}, 64)},
+		{"ecdsa_secp256k1_keccak256", Secp256k1PubHex, append(bytes.Repeat([]byte{0x01}, 64), 2)},
+		{"ed25519", Ed25519PubHex, append(make([]byte, 32), bytes.Repeat([]byte{0xff}, 32)...)},
+		{"schnorr_secp256k1_bip340", bip340TestCases[1].publicKey, bytes.Repeat([]byte{0xff}, 64)},
+		{"bls12381_minpk_pop", blsTestCases[0].pubKey, bytes.Repeat([]byte{0xff}, BLS12381SignatureLength)},
+	}
+	for _, tt := range testCases {
+		pubKey, _ := FromHex(tt.pubKeyHex)
+		verifier, err := NewVerifierForSuite(tt.suiteType, pubKey)
+		if err != nil {
+			t.Fatalf("Error creating the verifier: %s", err)
+		}
+		if err := VerifyErr(verifier, message, tt.signature); !errors.Is(err, ErrSignatureEncoding) {
+			t.Errorf("%s: got %v, expected %v", tt.suiteType, err, ErrSignatureEncoding)
+		}
+	}
+}
+
+func TestVerifyErrInvalidPublicKey(t *testing.T) {
+	// Edwards keys are only checked for their length when the verifier is
+	// created. A y coordinate of 2 is not on either curve.
+	offCurve25519 := append([]byte{2}, make([]byte, 31)...)
+	offCurve448 := append([]byte{2}, make([]byte, Ed448PublicKeyLength-1)...)
+	testCases := []struct {
+		verifier  func([]byte) (Verifier, error)
+		pubKey    []byte
+		signature []byte
+	}{
+		{NewEd25519Verifier, offCurve25519, make([]byte, 64)},
+		{NewEd25519phVerifier, offCurve25519, make([]byte, 64)},
+		{NewEd448Verifier, offCurve448, make([]byte, Ed448SignatureLength)},
+	}
+	for _, tt := range testCases {
+		verifier, err := tt.verifier(tt.pubKey)
+		if err != nil {
+			t.Fatalf("Error creating the verifier: %s", err)
+		}
+		if err := VerifyErr(verifier, []byte{1, 2, 3}, tt.signature); !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("%s: got %v, expected %v", verifier.SuiteType(), err, ErrInvalidPublicKey)
+		}
+	}
+
+	// Other suites reject invalid keys in the constructor.
+	p256OffCurve, _ := FromHex(P256PubHex)
+	p256OffCurve[63] ^= 1
+	secp256k1OffCurve, _ := FromHex(Secp256k1PubHex)
+	secp256k1OffCurve[63] ^= 1
+	constructors := []struct {
+		suiteType string
+		pubKey    []byte
+	}{
+		{"ecdsa_P256_sha-256", p256OffCurve},
+		{"ecdsa_P256_sha-256", p256OffCurve[:63]},
+		{"ecdsa_P256_sha3-256", p256OffCurve},
+		{"ecdsa_P256_sha3-256_det", p256OffCurve[:63]},
+		{"ecdsa_P256_shake256", p256OffCurve},
+		{"ecdsa_P256_shake256_rfc6979", p256OffCurve},
+		{"ecdsa_secp256k1_keccak256", secp256k1OffCurve},
+		{"ecdsa_secp256k1_sha-256", secp256k1OffCurve[:32]},
+		{"ed25519", offCurve25519[:31]},
+		{"ed448", offCurve448[:56]},
+		{"schnorr_secp256k1_bip340", bytes.Repeat([]byte{0xff}, 32)},
+		{"bls12381_minpk_pop", make([]byte, BLS12381PublicKeyLength)},
+		{"rsa_pss_sha-256", []byte{1, 2, 3}},
+	}
+	for _, tt := range constructors {
+		if _, err := NewVerifierForSuite(tt.suiteType, tt.pubKey); !errors.Is(err, ErrInvalidPublicKey) {
+			t.Errorf("%s: got %v, expected %v", tt.suiteType, err, ErrInvalidPublicKey)
+		}
+	}
+}
+
+// Hides the VerifyErr method of the verifier it wraps.
+type boolVerifier struct {
+	Verifier
+}
+
+func TestVerifyErrBoolVerifier(t *testing.T) {
+	signer := &MockSigner{Suite: "mock", VerifyRet: false}
+	if err := VerifyErr(boolVerifier{signer}, nil, nil); !errors.Is(err, ErrSignatureMismatch) {
+		t.Errorf("Got %v, expected %v", err, ErrSignatureMismatch)
+	}
+	signer.VerifyRet = true
+	if err := VerifyErr(boolVerifier{signer}, nil, nil); err != nil {
+		t.Errorf("Got %v, expected nil", err)
+	}
+}
+
+func TestPublicKeyHolder(t *testing.T) {
+	for _, tt := range registryTestCases {
+		t.Run(tt.suiteType, func(t *testing.T) {
+			privKey, _ := FromHex(tt.privKeyHex)
+			pubKey, _ := FromHex(tt.pubKeyHex)
+			signer, err := NewSignerForSuite(tt.suiteType, privKey)
+			if err != nil {
+				t.Fatalf("Error creating the signer: %s", err)
+			}
+			verifier, err := NewVerifierForSuite(tt.suiteType, pubKey)
+			if err != nil {
+				t.Fatalf("Error creating the verifier: %s", err)
+			}
+			holder, ok := verifier.(PublicKeyHolder)
+			if !ok {
+				t.Fatalf("Expected the verifier to hold a public key.")
+			}
+			if !bytes.Equal(signer.PublicKey(), pubKey) {
+				t.Errorf("Got %x, expected %x", signer.PublicKey(), pubKey)
+			}
+			if !bytes.Equal(holder.PublicKey(), pubKey) {
+				t.Errorf("Got %x, expected %x", holder.PublicKey(), pubKey)
+			}
+			keyID := KeyIDFromPublicKey(pubKey)
+			if signer.KeyID() != keyID || holder.KeyID() != keyID {
+				t.Errorf("Got %s and %s, expected %s", signer.KeyID(), holder.KeyID(), keyID)
+			}
+
+			// The key of a signer builds the verifier for its signatures.
+			message := []byte{1, 2, 3}
+			signature, _ := signer.Sign(message)
+			published, err := NewVerifierForSuite(signer.SuiteType(), signer.PublicKey())
+			if err != nil {
+				t.Fatalf("Error creating the verifier: %s", err)
+			}
+			if !published.Verify(message, signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+		})
+	}
+}
+
+func TestKeyIDFromPublicKey(t *testing.T) {
+	pubKey, _ := FromHex(Ed25519PubHex)
+	expected := "33df662b225412dcab74031b1de6052ce7fa7873671297c83a403f785960033b"
+	if keyID := KeyIDFromPublicKey(pubKey); keyID != expected {
+		t.Errorf("Got %s, expected %s", keyID, expected)
+	}
+}
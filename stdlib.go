@@ -0,0 +1,251 @@
+package crypto
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"hash"
+	"io"
+	"math/big"
+)
+
+// The standard library's crypto.Signer signs a digest computed by the caller
+// and names the hash in its SignerOpts, ECDSA signatures are ASN.1 DER. It is
+// what crypto/tls, crypto/x509 and golang.org/x/crypto/ssh sign with. The
+// adapters below convert between it and the Signer of this package.
+
+// Returns the standard library hash of a hasher, 0 if there is none.
+func stdHash(hasher Hasher) crypto.Hash {
+	switch hasher.(type) {
+	case *Sha_256Hasher:
+		return crypto.SHA256
+	case *Sha_384Hasher:
+		return crypto.SHA384
+	case *Sha_512Hasher:
+		return crypto.SHA512
+	case *Sha3_256Hasher:
+		return crypto.SHA3_256
+	case *Sha3_512Hasher:
+		return crypto.SHA3_512
+	}
+	return 0
+}
+
+// NewStdSigner wraps an ed25519, Ed25519ph or P256, P384 or P521 ECDSA signer
+// as a standard library crypto.Signer, e.g. to create certificates with
+// x509.CreateCertificate. Public returns an ed25519.PublicKey or an
+// *ecdsa.PublicKey. Sign checks that opts names the suite's hash, crypto.Hash(0)
+// for ed25519 which signs the message itself, and returns ECDSA signatures in
+// ASN.1 DER. The random source passed to Sign is not used, the signer signs as
+// it always does. Returns ErrUnexpectedSuite for other suites.
+func NewStdSigner(signer Signer) (crypto.Signer, error) {
+	switch s := signer.(type) {
+	case *ed25519Signer:
+		if s.options == nil || s.verifier.prehashed() {
+			var hashFunc crypto.Hash
+			if s.verifier.prehashed() {
+				hashFunc = crypto.SHA512
+			}
+			return &stdSigner{
+				signer: s,
+				public: ed25519.PublicKey(s.PublicKey()),
+				hash:   hashFunc,
+			}, nil
+		}
+	case *EcdsaSigner:
+		hashFunc := stdHash(s.hasher)
+		if hashFunc != 0 && isStdCurve(s.privateKey.Curve) {
+			public := s.privateKey.PublicKey
+			return &stdSigner{signer: s, public: &public, hash: hashFunc}, nil
+		}
+	}
+	return nil, fmt.Errorf(
+		"%w: %s can not be used as a crypto.Signer", ErrUnexpectedSuite, signer.SuiteType())
+}
+
+func isStdCurve(curve elliptic.Curve) bool {
+	return curve == elliptic.P256() || curve == elliptic.P384() || curve == elliptic.P521()
+}
+
+type stdSigner struct {
+	signer Signer
+	public crypto.PublicKey
+	hash   crypto.Hash
+}
+
+func (s *stdSigner) Public() crypto.PublicKey {
+	return s.public
+}
+
+func (s *stdSigner) Sign(_ io.Reader, digest []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if opts == nil || opts.HashFunc() != s.hash {
+		return nil, fmt.Errorf("%w: %s signs with %s", ErrUnexpectedHash, s.signer.SuiteType(), s.hash)
+	}
+	if options, ok := opts.(*ed25519.Options); ok && options.Context != "" {
+		return nil, fmt.Errorf("%w: %s does not sign with a context", ErrUnexpectedSuite, s.signer.SuiteType())
+	}
+	if s.hash == 0 {
+		// Pure ed25519 signs the message itself.
+		return s.signer.Sign(digest)
+	}
+	signature, err := s.signer.(DigestSigner).SignDigest(digest)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := s.signer.(*EcdsaSigner); ok {
+		return EcdsaSignatureToASN1(signature)
+	}
+	return signature, nil
+}
+
+// NewSignerFromStd wraps a standard library crypto.Signer, e.g. an
+// *ecdsa.PrivateKey loaded with PrivateKeyFromPEMFile or a key held in a
+// hardware module, as a Signer. The suite is chosen by the type of the public
+// key:
+//
+//	P256 *ecdsa.PublicKey   ecdsa_P256_sha-256_indet
+//	P384 *ecdsa.PublicKey   ecdsa_P384_sha-384_indet
+//	P521 *ecdsa.PublicKey   ecdsa_P521_sha-512_indet
+//	ed25519.PublicKey       ed25519
+//	*rsa.PublicKey          rsa_pss_sha-256
+//
+// ECDSA signatures are converted to R || S with a low S. Returns
+// ErrUnexpectedKeyType for other keys.
+func NewSignerFromStd(signer crypto.Signer) (Signer, error) {
+	var suiteType string
+	var pubKey []byte
+	switch public := signer.Public().(type) {
+	case *ecdsa.PublicKey:
+		switch public.Curve {
+		case elliptic.P256():
+			suiteType = "ecdsa_P256_sha-256_indet"
+		case elliptic.P384():
+			suiteType = "ecdsa_P384_sha-384_indet"
+		case elliptic.P521():
+			suiteType = "ecdsa_P521_sha-512_indet"
+		default:
+			return nil, fmt.Errorf("%w: unsupported curve %s", ErrUnexpectedKeyType, public.Params().Name)
+		}
+		pubKey = ecdsaPublicKeyBytes(public)
+	case ed25519.PublicKey:
+		suiteType = "ed25519"
+		pubKey = public
+	case *rsa.PublicKey:
+		suiteType = "rsa_pss_sha-256"
+		var err error
+		if pubKey, err = MarshalRSAPublicKeyPKIX(public); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnexpectedKeyType, public)
+	}
+
+	verifier, err := NewVerifierForSuite(suiteType, pubKey)
+	if err != nil {
+		return nil, err
+	}
+	wrapped := &signerFromStd{signer: signer, suiteType: suiteType}
+	switch v := verifier.(type) {
+	case *EcdsaVerifier:
+		wrapped.verifier, wrapped.hasher = v, v.hasher
+	case *rsaVerifier:
+		wrapped.verifier, wrapped.hasher = v, v.hasher
+	case *ed25519Verifier:
+		wrapped.verifier = v
+	}
+	return wrapped, nil
+}
+
+type signerFromStd struct {
+	signer crypto.Signer
+	// hasher is nil for ed25519, which signs the message itself.
+	hasher   Hasher
+	verifier interface {
+		ErrorVerifier
+		PublicKeyHolder
+	}
+	suiteType string
+}
+
+func (s *signerFromStd) Sign(toSign []byte) ([]byte, error) {
+	if s.hasher == nil {
+		return s.signer.Sign(rand.Reader, toSign, crypto.Hash(0))
+	}
+	return s.signDigest(s.hasher.Hash(toSign))
+}
+
+func (s *signerFromStd) signDigest(digest []byte) ([]byte, error) {
+	hashFunc := stdHash(s.hasher)
+	switch v := s.verifier.(type) {
+	case *rsaVerifier:
+		opts := &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: hashFunc}
+		return s.signer.Sign(rand.Reader, digest, opts)
+	case *EcdsaVerifier:
+		der, err := s.signer.Sign(rand.Reader, digest, hashFunc)
+		if err != nil {
+			return nil, err
+		}
+		R, S, err := DecodeEcdsaSignatureASN1(der)
+		if err != nil {
+			return nil, err
+		}
+		N := v.publicKey.Curve.Params().N
+		if isHighS(N, S) {
+			S = new(big.Int).Sub(N, S)
+		}
+		return EncodeEcdsaSignature(R, S, ecdsaScalarLength(v.publicKey.Curve))
+	}
+	return nil, fmt.Errorf("%w: %s", ErrSuiteCannotPrehash, s.suiteType)
+}
+
+func (s *signerFromStd) newHash() hash.Hash {
+	if s.hasher == nil {
+		return nil
+	}
+	hashFunc, _ := newStreamHash(s.hasher)
+	return hashFunc
+}
+
+// SignDigest signs a digest computed with the suite's hash, see DigestSigner.
+func (s *signerFromStd) SignDigest(digest []byte) ([]byte, error) {
+	return signCheckedDigest(s, digest)
+}
+
+func (s *signerFromStd) Verify(toVerify []byte, signature []byte) bool {
+	return s.verifier.Verify(toVerify, signature)
+}
+
+// VerifyErr verifies a signature, returning why it failed, see ErrorVerifier.
+func (s *signerFromStd) VerifyErr(toVerify []byte, signature []byte) error {
+	return s.verifier.VerifyErr(toVerify, signature)
+}
+
+func (s *signerFromStd) verifyDigest(digest []byte, signature []byte) error {
+	if v, ok := s.verifier.(digestVerifier); ok {
+		return v.verifyDigest(digest, signature)
+	}
+	return fmt.Errorf("%w: %s", ErrSuiteCannotPrehash, s.suiteType)
+}
+
+// VerifyDigest verifies a signature over a digest, see DigestVerifier.
+func (s *signerFromStd) VerifyDigest(digest []byte, signature []byte) bool {
+	return verifyCheckedDigest(s, digest, signature)
+}
+
+// PublicKey returns the public key, see PublicKeyHolder.
+func (s *signerFromStd) PublicKey() []byte {
+	return s.verifier.PublicKey()
+}
+
+// KeyID returns the key ID of the public key, see PublicKeyHolder.
+func (s *signerFromStd) KeyID() string {
+	return s.verifier.KeyID()
+}
+
+func (s *signerFromStd) SuiteType() string {
+	return s.suiteType
+}
@@ -0,0 +1,272 @@
+package crypto
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNewStdSignerEcdsa(t *testing.T) {
+	privKey, _ := FromHex(P256PrivHex)
+	pubKey, _ := FromHex(P256PubHex)
+	signer, _ := NewP256Sha_256RFC6979Signer(privKey)
+	stdSigner, err := NewStdSigner(signer)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	public, ok := stdSigner.Public().(*ecdsa.PublicKey)
+	if !ok || public.Curve != elliptic.P256() {
+		t.Fatalf("Got %T, expected a P256 *ecdsa.PublicKey", stdSigner.Public())
+	}
+	X, Y := splitByteSlice(pubKey)
+	if public.X.Cmp(X) != 0 || public.Y.Cmp(Y) != 0 {
+		t.Errorf("Got a different public key.")
+	}
+
+	message := []byte{1, 2, 3}
+	digest := (&Sha_256Hasher{}).Hash(message)
+	der, err := stdSigner.Sign(rand.Reader, digest, crypto.SHA256)
+	if err != nil {
+		t.Fatalf("Error signing: %s", err)
+	}
+	if !ecdsa.VerifyASN1(public, digest, der) {
+		t.Errorf("Expected standard library to verify signature.")
+	}
+	signature, _ := EcdsaSignatureFromASN1(der, 32)
+	if !signer.Verify(message, signature) {
+		t.Errorf("Expected to verify signature.")
+	}
+
+	if _, err := stdSigner.Sign(rand.Reader, digest, crypto.SHA384); !errors.Is(err, ErrUnexpectedHash) {
+		t.Errorf("Got %v, expected %v", err, ErrUnexpectedHash)
+	}
+	if _, err := stdSigner.Sign(rand.Reader, digest[1:], crypto.SHA256); !errors.Is(err, ErrDigestLength) {
+		t.Errorf("Got %v, expected %v", err, ErrDigestLength)
+	}
+}
+
+func TestNewStdSignerEd25519(t *testing.T) {
+	privKey, _ := FromHex(Ed25519PrivHex)
+	pubKey, _ := FromHex(Ed25519PubHex)
+	message := []byte{1, 2, 3}
+
+	signer, _ := NewEd25519Signer(privKey)
+	stdSigner, err := NewStdSigner(signer)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	if public, ok := stdSigner.Public().(ed25519.PublicKey); !ok || !public.Equal(ed25519.PublicKey(pubKey)) {
+		t.Errorf("Got %v, expected the ed25519 public key", stdSigner.Public())
+	}
+	signature, err := stdSigner.Sign(nil, message, crypto.Hash(0))
+	if err != nil {
+		t.Fatalf("Error signing: %s", err)
+	}
+	if !ed25519.Verify(pubKey, message, signature) {
+		t.Errorf("Expected standard library to verify signature.")
+	}
+	if _, err := stdSigner.Sign(nil, message, &ed25519.Options{Context: "ctx"}); !errors.Is(err, ErrUnexpectedSuite) {
+		t.Errorf("Got %v, expected %v", err, ErrUnexpectedSuite)
+	}
+	if _, err := stdSigner.Sign(nil, message, crypto.SHA512); !errors.Is(err, ErrUnexpectedHash) {
+		t.Errorf("Got %v, expected %v", err, ErrUnexpectedHash)
+	}
+
+	phSigner, _ := NewEd25519phSigner(privKey)
+	stdPhSigner, err := NewStdSigner(phSigner)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	digest := sha512.Sum512(message)
+	opts := &ed25519.Options{Hash: crypto.SHA512}
+	signature, err = stdPhSigner.Sign(nil, digest[:], opts)
+	if err != nil {
+		t.Fatalf("Error signing: %s", err)
+	}
+	if err := ed25519.VerifyWithOptions(pubKey, digest[:], signature, opts); err != nil {
+		t.Errorf("Expected standard library to verify signature: %s", err)
+	}
+}
+
+func TestNewStdSignerUnsupported(t *testing.T) {
+	p256PrivKey, _ := FromHex(P256PrivHex)
+	k1PrivKey, _ := FromHex(Secp256k1PrivHex)
+	edPrivKey, _ := FromHex(Ed25519PrivHex)
+	shake, _ := NewP256Shake256RFC6979Signer(p256PrivKey)
+	k1, _ := NewSecp256k1Sha_256Signer(k1PrivKey)
+	ctx, _ := NewEd25519ctxSigner(edPrivKey, []byte("ctx"))
+	for _, signer := range []Signer{shake, k1, ctx, &MockSigner{}} {
+		if _, err := NewStdSigner(signer); !errors.Is(err, ErrUnexpectedSuite) {
+			t.Errorf("%s: got %v, expected %v", signer.SuiteType(), err, ErrUnexpectedSuite)
+		}
+	}
+}
+
+// The adapters are meant for APIs such as x509.CreateCertificate.
+func TestNewStdSignerCertificate(t *testing.T) {
+	p256PrivKey, _ := FromHex(P256PrivHex)
+	edPrivKey, _ := FromHex(Ed25519PrivHex)
+	p256, _ := NewP256Sha_256RFC6979Signer(p256PrivKey)
+	p256Sha3, _ := NewP256Sha3_256RFC6979Signer(p256PrivKey)
+	ed, _ := NewEd25519Signer(edPrivKey)
+	testCases := []struct {
+		signer    Signer
+		algorithm x509.SignatureAlgorithm
+	}{
+		{p256, x509.ECDSAWithSHA256},
+		{ed, x509.PureEd25519},
+		{p256Sha3, x509.ECDSAWithSHA256},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.signer.SuiteType(), func(t *testing.T) {
+			stdSigner, err := NewStdSigner(tt.signer)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			template := &x509.Certificate{
+				SerialNumber: big.NewInt(1),
+				Subject:      pkix.Name{CommonName: "test"},
+				NotBefore:    time.Unix(0, 0),
+				NotAfter:     time.Unix(0, 0).Add(time.Hour),
+			}
+			der, err := x509.CreateCertificate(rand.Reader, template, template, stdSigner.Public(), stdSigner)
+			if tt.signer.SuiteType() == "ecdsa_P256_sha3-256_rfc6979" {
+				// x509 hashes with SHA-256, the signer with SHA3-256.
+				if !errors.Is(err, ErrUnexpectedHash) {
+					t.Errorf("Got %v, expected %v", err, ErrUnexpectedHash)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Error creating the certificate: %s", err)
+			}
+			cert, err := x509.ParseCertificate(der)
+			if err != nil {
+				t.Fatalf("Error parsing the certificate: %s", err)
+			}
+			if cert.SignatureAlgorithm != tt.algorithm {
+				t.Errorf("Got %s, expected %s", cert.SignatureAlgorithm, tt.algorithm)
+			}
+			if err := cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature); err != nil {
+				t.Errorf("Error checking the signature: %s", err)
+			}
+		})
+	}
+}
+
+func TestNewSignerFromStd(t *testing.T) {
+	p256, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	p384, _ := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	p521, _ := ecdsa.GenerateKey(elliptic.P521(), rand.Reader)
+	_, ed, _ := ed25519.GenerateKey(rand.Reader)
+	rsaDER, _ := FromHex(RSAPrivHex)
+	rsaKey, _ := UnmarshalRSAPrivateKey(rsaDER)
+	testCases := []struct {
+		stdSigner crypto.Signer
+		suiteType string
+	}{
+		{p256, "ecdsa_P256_sha-256_indet"},
+		{p384, "ecdsa_P384_sha-384_indet"},
+		{p521, "ecdsa_P521_sha-512_indet"},
+		{ed, "ed25519"},
+		{rsaKey, "rsa_pss_sha-256"},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.suiteType, func(t *testing.T) {
+			signer, err := NewSignerFromStd(tt.stdSigner)
+			if err != nil {
+				t.Fatalf("Unexpected error: %s", err)
+			}
+			if signer.SuiteType() != tt.suiteType {
+				t.Errorf("Got suite %s, expected %s", signer.SuiteType(), tt.suiteType)
+			}
+			verifier, err := NewVerifierForSuite(signer.SuiteType(), signer.PublicKey())
+			if err != nil {
+				t.Fatalf("Error creating the verifier: %s", err)
+			}
+			if verifier.(PublicKeyHolder).KeyID() != signer.KeyID() {
+				t.Errorf("Expected the verifier to have the signer's key ID.")
+			}
+			strict, err := NewLowSVerifier(verifier)
+			if err != nil {
+				strict = verifier
+			}
+			for i := 0; i < 8; i++ {
+				message := []byte{byte(i)}
+				signature, err := signer.Sign(message)
+				if err != nil {
+					t.Fatalf("Error signing: %s", err)
+				}
+				if err := VerifyErr(strict, message, signature); err != nil {
+					t.Errorf("Error verifying: %s", err)
+				}
+				if !signer.Verify(message, signature) {
+					t.Errorf("Expected to verify signature.")
+				}
+			}
+
+			// Suites that sign a hash of the message can stream it.
+			message := strings.NewReader("streamed")
+			signature, err := SignReader(signer, message)
+			if tt.suiteType == "ed25519" {
+				if !errors.Is(err, ErrSuiteCannotPrehash) {
+					t.Errorf("Got %v, expected %v", err, ErrSuiteCannotPrehash)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Error signing: %s", err)
+			}
+			if !verifier.Verify([]byte("streamed"), signature) {
+				t.Errorf("Expected to verify signature.")
+			}
+		})
+	}
+}
+
+func TestNewSignerFromStdUnsupported(t *testing.T) {
+	p224, _ := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+	if _, err := NewSignerFromStd(p224); !errors.Is(err, ErrUnexpectedKeyType) {
+		t.Errorf("Got %v, expected %v", err, ErrUnexpectedKeyType)
+	}
+	_, ed, _ := ed25519.GenerateKey(rand.Reader)
+	if _, err := NewSignerFromStd(otherKeySigner{ed}); !errors.Is(err, ErrUnexpectedKeyType) {
+		t.Errorf("Got %v, expected %v", err, ErrUnexpectedKeyType)
+	}
+}
+
+// A crypto.Signer with a public key type this package does not know.
+type otherKeySigner struct {
+	crypto.Signer
+}
+
+func (s otherKeySigner) Public() crypto.PublicKey {
+	return []byte{1, 2, 3}
+}
+
+func TestStdSignerRoundTrip(t *testing.T) {
+	privKey, _ := FromHex(P384PrivHex)
+	signer, _ := NewP384Sha_384RFC6979Signer(privKey)
+	stdSigner, _ := NewStdSigner(signer)
+	wrapped, err := NewSignerFromStd(stdSigner)
+	if err != nil {
+		t.Fatalf("Unexpected error: %s", err)
+	}
+	message := []byte{1, 2, 3}
+	signature, _ := wrapped.Sign(message)
+	expected, _ := signer.Sign(message)
+	if !bytes.Equal(signature, expected) {
+		t.Errorf("Got %x, expected %x", signature, expected)
+	}
+}
@@ -0,0 +1,70 @@
+package crypto
+
+import (
+	"fmt"
+	"hash"
+	"io"
+)
+
+// Suites that sign a hash of the message, e.g. the ECDSA and RSA suites and
+// Ed25519ph, can hash the message separately from signing it. This lets
+// SignReader and VerifyReader stream a message of any size through the hash
+// instead of holding it in memory. Suites that sign the message itself, such
+// as pure Ed25519, do not support streaming.
+
+// digestSigner is implemented by signers that sign a hash of the message.
+type digestSigner interface {
+	Signer
+	// newHash returns the hash used for messages, or nil if the hasher can not
+	// be used incrementally.
+	newHash() hash.Hash
+	signDigest(digest []byte) ([]byte, error)
+}
+
+// digestVerifier is implemented by verifiers that verify a hash of the
+// message.
+type digestVerifier interface {
+	Verifier
+	newHash() hash.Hash
+	verifyDigest(digest []byte, signature []byte) error
+}
+
+// SignReader signs everything read from r until EOF with signer. The
+// signature is the same as signer.Sign would create over the same bytes.
+// Returns ErrSuiteCannotPrehash if the suite signs messages directly.
+func SignReader(signer Signer, r io.Reader) ([]byte, error) {
+	digester, ok := signer.(digestSigner)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrSuiteCannotPrehash, signer.SuiteType())
+	}
+	digest, err := hashReader(digester.newHash(), digester.SuiteType(), r)
+	if err != nil {
+		return nil, err
+	}
+	return digester.signDigest(digest)
+}
+
+// VerifyReader verifies signature over everything read from r until EOF.
+// Returns an error if r can not be read or the suite verifies messages
+// directly, see SignReader.
+func VerifyReader(verifier Verifier, r io.Reader, signature []byte) (bool, error) {
+	digester, ok := verifier.(digestVerifier)
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrSuiteCannotPrehash, verifier.SuiteType())
+	}
+	digest, err := hashReader(digester.newHash(), digester.SuiteType(), r)
+	if err != nil {
+		return false, err
+	}
+	return digester.verifyDigest(digest, signature) == nil, nil
+}
+
+func hashReader(hashFunc hash.Hash, suiteType string, r io.Reader) ([]byte, error) {
+	if hashFunc == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSuiteCannotPrehash, suiteType)
+	}
+	if _, err := io.Copy(hashFunc, r); err != nil {
+		return nil, err
+	}
+	return hashFunc.Sum(nil), nil
+}
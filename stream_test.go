@@ -0,0 +1,95 @@
+package crypto
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+var nonStreamingSuites = map[string]bool{
+	"ed25519":                  true,
+	"ed448":                    true,
+	"schnorr_secp256k1_bip340": true,
+	"bls12381_minpk_pop":       true,
+}
+
+func TestSignReader(t *testing.T) {
+	message := bytes.Repeat([]byte("a large file "), 10000)
+	for _, tt := range registryTestCases {
+		t.Run(tt.suiteType, func(t *testing.T) {
+			privKey, _ := FromHex(tt.privKeyHex)
+			pubKey, _ := FromHex(tt.pubKeyHex)
+			signer, _ := NewSignerForSuite(tt.suiteType, privKey)
+			verifier, _ := NewVerifierForSuite(tt.suiteType, pubKey)
+
+			streamed, err := SignReader(signer, bytes.NewReader(message))
+			if nonStreamingSuites[tt.suiteType] {
+				if !errors.Is(err, ErrSuiteCannotPrehash) {
+					t.Errorf("Expected ErrSuiteCannotPrehash, got %v", err)
+				}
+				_, err = VerifyReader(verifier, bytes.NewReader(message), nil)
+				if !errors.Is(err, ErrSuiteCannotPrehash) {
+					t.Errorf("Expected ErrSuiteCannotPrehash, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Error signing: %s", err)
+			}
+			if !verifier.Verify(message, streamed) {
+				t.Errorf("Expected streamed signature to verify.")
+			}
+
+			signature, _ := signer.Sign(message)
+			ok, err := VerifyReader(verifier, iotest.OneByteReader(bytes.NewReader(message)), signature)
+			if err != nil || !ok {
+				t.Errorf("Expected signature to verify when streamed, got %v", err)
+			}
+			ok, _ = VerifyReader(verifier, bytes.NewReader(message[1:]), signature)
+			if ok {
+				t.Errorf("Expected signature over another message to not verify.")
+			}
+		})
+	}
+}
+
+func TestSignReaderDeterministic(t *testing.T) {
+	privKey, _ := FromHex(P256PrivHex)
+	signer, _ := NewP256Sha_256RFC6979Signer(privKey)
+	message := "hello world"
+	streamed, _ := SignReader(signer, strings.NewReader(message))
+	signature, _ := signer.Sign([]byte(message))
+	if !bytes.Equal(streamed, signature) {
+		t.Errorf("Expected %x, got %x", signature, streamed)
+	}
+}
+
+func TestSignReaderError(t *testing.T) {
+	privKey, _ := FromHex(P256PrivHex)
+	signer, _ := NewP256Sha_256RFC6979Signer(privKey)
+	readErr := errors.New("read failed")
+	reader := io.MultiReader(strings.NewReader("hello"), iotest.ErrReader(readErr))
+
+	if _, err := SignReader(signer, reader); !errors.Is(err, readErr) {
+		t.Errorf("Expected read error, got %v", err)
+	}
+	if _, err := VerifyReader(signer, iotest.ErrReader(readErr), nil); !errors.Is(err, readErr) {
+		t.Errorf("Expected read error, got %v", err)
+	}
+}
+
+func TestSignReaderEthereumAddress(t *testing.T) {
+	privKey, _ := FromHex(Secp256k1PrivHex)
+	signer, _ := NewSecp256k1Keccak256Signer(privKey)
+	address, _ := EthereumAddressFromHex(Secp256k1AddressHex)
+	verifier, _ := NewSecp256k1Keccak256AddressVerifier(address)
+
+	signature, _ := SignReader(signer, strings.NewReader("hello world"))
+	ok, err := VerifyReader(verifier, strings.NewReader("hello world"), signature)
+	if err != nil || !ok {
+		t.Errorf("Expected signature to verify against the address, got %v", err)
+	}
+}
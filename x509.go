@@ -2,7 +2,9 @@ package crypto
 
 import (
 	"crypto/ecdsa"
+	"crypto/rsa"
 	"crypto/x509"
+	"fmt"
 )
 
 // MarhsalPrivateKeyX509 serialize a private key to DER-encoded format. (wrapper around x509 in crypto pkg )
@@ -35,9 +37,63 @@ func MarshaPublicKeyX509(pubk *ecdsa.PublicKey) ([]byte, error) {
 // UnmarshalPublicKeyX509 parses a DER encoded public key. (wrapper around x509 in crypto pkg)
 func UnmarshalPublicKeyX509(der []byte) (*ecdsa.PublicKey, error) {
 	key, err := x509.ParsePKIXPublicKey(der)
-	eckey := key.(*ecdsa.PublicKey)
 	if err != nil {
 		return nil, err
 	}
-	return (*ecdsa.PublicKey)(eckey), nil
+	eckey, ok := key.(*ecdsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected an ECDSA key, got %T", ErrUnexpectedKeyType, key)
+	}
+	return eckey, nil
+}
+
+// MarshalRSAPrivateKeyPKCS1 serialize an RSA private key to DER-encoded PKCS#1 format. (wrapper around x509 in crypto pkg)
+func MarshalRSAPrivateKeyPKCS1(prvk *rsa.PrivateKey) ([]byte, error) {
+	if prvk == nil || prvk.N == nil {
+		return nil, fmt.Errorf("%w: RSA private key is empty", ErrUnexpectedKeyType)
+	}
+	return x509.MarshalPKCS1PrivateKey(prvk), nil
+}
+
+// UnmarshalRSAPrivateKey parses a DER encoded RSA private key in either PKCS#1
+// or PKCS#8 format. (wrapper around x509 in crypto pkg)
+func UnmarshalRSAPrivateKey(der []byte) (*rsa.PrivateKey, error) {
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, err
+	}
+	rsakey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected an RSA key, got %T", ErrUnexpectedKeyType, key)
+	}
+	return rsakey, nil
+}
+
+// MarshalRSAPublicKeyPKIX serialize an RSA public key to DER-encoded PKIX format. (wrapper around x509 in crypto pkg)
+func MarshalRSAPublicKeyPKIX(pubk *rsa.PublicKey) ([]byte, error) {
+	x509EncodePubKey, err := x509.MarshalPKIXPublicKey(pubk)
+	if err != nil {
+		return nil, err
+	}
+	return x509EncodePubKey, nil
+}
+
+// UnmarshalRSAPublicKey parses a DER encoded RSA public key in either PKIX or
+// PKCS#1 format. (wrapper around x509 in crypto pkg)
+func UnmarshalRSAPublicKey(der []byte) (*rsa.PublicKey, error) {
+	if key, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return key, nil
+	}
+	key, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, err
+	}
+	rsakey, ok := key.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected an RSA key, got %T", ErrUnexpectedKeyType, key)
+	}
+	return rsakey, nil
 }
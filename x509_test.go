@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 )
@@ -67,3 +68,11 @@ func TestDeterministicMarshalling(t *testing.T) {
 		t.Error("Marshalled keys do not match")
 	}
 }
+
+func TestUnmarshalPublicKeyX509WrongKeyType(t *testing.T) {
+	der, _ := FromHex(RSAPubHex)
+	_, err := UnmarshalPublicKeyX509(der)
+	if !errors.Is(err, ErrUnexpectedKeyType) {
+		t.Errorf("Expected ErrUnexpectedKeyType, got %v", err)
+	}
+}